@@ -0,0 +1,78 @@
+package blockexplorer
+
+import (
+	"reflect"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+)
+
+var publicHashType = reflect.TypeOf(common.PublicHash{})
+
+type accountInfo struct {
+	Address  string   `json:"Address"`
+	Type     string   `json:"Type"`
+	Name     string   `json:"Name"`
+	Balance  string   `json:"Balance"`
+	KeyHash  string   `json:"KeyHash,omitempty"`
+	Signers  []string `json:"Signers,omitempty"`
+	Required int      `json:"Required,omitempty"`
+	TxCount  uint32   `json:"TxCount"`
+}
+
+// accountInfoOf loads the current state of the account from the kernel
+func (e *BlockExplorer) accountInfoOf(addr common.Address) (*accountInfo, error) {
+	acc, err := e.Kernel.Loader().Account(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &accountInfo{
+		Address: addr.String(),
+		TxCount: e.AddressTxCount(addr),
+	}
+	if name, err := e.Kernel.Accounter().NameByType(acc.Type()); err == nil {
+		info.Type = name
+	}
+	if a, ok := acc.(interface{ Name() string }); ok {
+		info.Name = a.Name()
+	}
+	if a, ok := acc.(interface{ Balance() *amount.Amount }); ok {
+		if b := a.Balance(); b != nil {
+			info.Balance = b.String()
+		}
+	}
+	accountKeys(acc, info)
+	return info, nil
+}
+
+// accountKeys fills the key hash of the single key accounts or the signers of the multi-sig accounts
+func accountKeys(acc account.Account, info *accountInfo) {
+	walkFields(reflect.ValueOf(acc), func(f reflect.StructField, fv reflect.Value) bool {
+		switch {
+		case f.Type == publicHashType:
+			info.KeyHash = fv.Interface().(common.PublicHash).String()
+		case fv.Kind() == reflect.Slice && f.Type.Elem() == publicHashType:
+			for j := 0; j < fv.Len(); j++ {
+				info.Signers = append(info.Signers, fv.Index(j).Interface().(common.PublicHash).String())
+			}
+		case f.Name == "Required":
+			switch fv.Kind() {
+			case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+				info.Required = int(fv.Uint())
+			case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+				info.Required = int(fv.Int())
+			}
+		}
+		return true
+	})
+}
+
+func (e *BlockExplorer) accountData(addrStr string) (*accountInfo, error) {
+	addr, err := parseAddressParam(addrStr)
+	if err != nil {
+		return nil, err
+	}
+	return e.accountInfoOf(addr)
+}
@@ -0,0 +1,155 @@
+package blockexplorer
+
+import (
+	"reflect"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/transaction"
+)
+
+var addressType = reflect.TypeOf(common.Address{})
+
+// accountCreateTxTypes are transactions creating an account at the coordinate of the transaction
+var accountCreateTxTypes = map[string]bool{
+	"fleta.CreateAccount":         true,
+	"fleta.CreateMultiSigAccount": true,
+	"fleta.OpenAccount":           true,
+	"consensus.CreateFormulation": true,
+	"solidity.CreateContract":     true,
+}
+
+// txAddresses returns the addresses touched by the transaction : the signer, the recipients and the created account
+func (e *BlockExplorer) txAddresses(tx transaction.Transaction, height uint32, index uint32) []common.Address {
+	addrs := []common.Address{}
+	has := map[common.Address]bool{}
+	collectAddresses(reflect.ValueOf(tx), has, &addrs)
+
+	name, _ := e.source.Transactor().NameByType(tx.Type())
+	if accountCreateTxTypes[name] {
+		addr := common.NewAddress(common.NewCoordinate(height, uint16(index)), 0)
+		if !has[addr] {
+			has[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// collectAddresses appends the addresses in the value and in the fields, the slices and the arrays of it
+func collectAddresses(v reflect.Value, has map[common.Address]bool, addrs *[]common.Address) {
+	v, ok := indirect(v)
+	if !ok {
+		return
+	}
+	switch {
+	case v.Type() == addressType:
+		addr := v.Interface().(common.Address)
+		if addr != (common.Address{}) && !has[addr] {
+			has[addr] = true
+			*addrs = append(*addrs, addr)
+		}
+	case v.Kind() == reflect.Array || v.Kind() == reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			collectAddresses(v.Index(i), has, addrs)
+		}
+	default:
+		walkFields(v, func(f reflect.StructField, fv reflect.Value) bool {
+			collectAddresses(fv, has, addrs)
+			return true
+		})
+	}
+}
+
+func (e *BlockExplorer) updateAddressTxs(it *indexTxn, b *block.Block, height uint32) error {
+	for i, tx := range b.Body.Transactions {
+		for _, addr := range e.txAddresses(tx, height, uint32(i)) {
+			if err := it.Set(addressTxKey(addr, height, uint32(i)), []byte{}); err != nil {
+				return err
+			}
+			if err := it.Increase(addressTxCountKey(addr)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AddressTxCount returns the number of transactions related to the address
+func (e *BlockExplorer) AddressTxCount(addr common.Address) (count uint32) {
+	e.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(addressTxCountKey(addr))
+		if err != nil {
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		count = util.BytesToUint32(value)
+		return nil
+	})
+	return
+}
+
+// addressTxPositions returns the positions of the transactions of the address from the newest one skipping start
+// the height and the index of the keys are big endian so the reverse iteration follows the chain order
+func addressTxPositions(txn *badger.Txn, addr common.Address, start int, length int) []txPosition {
+	positions := []txPosition{}
+	prefix := addressTxPrefixKey(addr)
+
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	opts.Reverse = true
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	skipped := 0
+	for it.Seek(append(append([]byte{}, prefix...), 0xFF)); it.ValidForPrefix(prefix) && len(positions) < length; it.Next() {
+		if skipped < start {
+			skipped++
+			continue
+		}
+		key := it.Item().Key()[len(prefix):]
+		positions = append(positions, txPosition{
+			height: keyToUint32(key[0:4]),
+			index:  keyToUint32(key[4:8]),
+		})
+	}
+	return positions
+}
+
+func (e *BlockExplorer) addressTxList(addr common.Address, start int, length int) []txInfos {
+	var positions []txPosition
+	e.db.View(func(txn *badger.Txn) error {
+		positions = addressTxPositions(txn, addr, start, length)
+		return nil
+	})
+	return e.txInfosByPosition(positions)
+}
+
+func (e *BlockExplorer) addressTxs(addrStr string, startStr string) (result txInfosCase, err error) {
+	result.AaData = []txInfos{}
+
+	addr, err := parseAddressParam(addrStr)
+	if err != nil {
+		return result, err
+	}
+	start, err := parseStart(startStr)
+	if err != nil {
+		return result, err
+	}
+
+	count := int(e.AddressTxCount(addr))
+	result.ITotalRecords = count
+	result.ITotalDisplayRecords = count
+
+	result.AaData = e.addressTxList(addr, start, 10)
+
+	return
+}
@@ -0,0 +1,44 @@
+package blockexplorer
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+)
+
+func TestAddressTxPositionsNewestFirst(t *testing.T) {
+	db, closeDB := openTestDB(t)
+	defer closeDB()
+
+	addr := common.NewAddress(common.NewCoordinate(1, 0), 0)
+	written := []txPosition{{1, 0}, {255, 3}, {256, 0}, {256, 1}, {70000, 2}}
+	if err := db.Update(func(txn *badger.Txn) error {
+		for _, p := range written {
+			if err := txn.Set(addressTxKey(addr, p.height, p.index), []byte{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	db.View(func(txn *badger.Txn) error {
+		positions := addressTxPositions(txn, addr, 0, len(written))
+		if len(positions) != len(written) {
+			t.Fatalf("%d positions, expected %d", len(positions), len(written))
+		}
+		for i, p := range positions {
+			if expected := written[len(written)-1-i]; p != expected {
+				t.Errorf("position %d is %v, expected %v", i, p, expected)
+			}
+		}
+
+		page := addressTxPositions(txn, addr, 2, 2)
+		if len(page) != 2 || page[0] != written[2] || page[1] != written[1] {
+			t.Errorf("second page is %v", page)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,328 @@
+package blockexplorer
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+	"github.com/labstack/echo"
+)
+
+// apiPrefix is the path prefix of the versioned REST API
+const apiPrefix = "/api/v1"
+
+// api list limits
+const (
+	apiDefaultLimit = 10
+	apiMaxLimit     = 100
+)
+
+// api error codes
+const (
+	apiInvalidParameter = "invalid_parameter"
+	apiNotFound         = "not_found"
+	apiInternal         = "internal"
+)
+
+type apiErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// apiError is the body of every failed api response
+type apiError struct {
+	Error apiErrorBody `json:"error"`
+}
+
+type apiBlock struct {
+	Height         uint32   `json:"height"`
+	Hash           string   `json:"hash"`
+	PrevHash       string   `json:"prevHash"`
+	ChainCoord     string   `json:"chainCoord"`
+	Version        int      `json:"version"`
+	Timestamp      string   `json:"timestamp"`
+	Formulator     string   `json:"formulator"`
+	FormulatorName string   `json:"formulatorName,omitempty"`
+	TimeoutCount   int      `json:"timeoutCount"`
+	TxCount        int      `json:"txCount"`
+	Txs            []string `json:"txs"`
+}
+
+type apiBlockList struct {
+	Blocks     []apiBlock `json:"blocks"`
+	NextCursor *uint32    `json:"nextCursor"`
+}
+
+type apiTx struct {
+	Hash           string            `json:"hash"`
+	Type           string            `json:"type"`
+	Timestamp      string            `json:"timestamp"`
+	BlockHeight    uint32            `json:"blockHeight"`
+	BlockHash      string            `json:"blockHash"`
+	BlockTimestamp string            `json:"blockTimestamp"`
+	Index          uint32            `json:"index"`
+	Names          map[string]string `json:"names,omitempty"`
+	UTXO           *utxoDetail       `json:"utxo,omitempty"`
+	Payload        json.RawMessage   `json:"payload"`
+}
+
+type apiChainInfo struct {
+	Height        uint32             `json:"height"`
+	IndexedHeight uint32             `json:"indexedHeight"`
+	Formulators   int                `json:"formulators"`
+	Transactions  int                `json:"transactions"`
+	Tps           map[string]float64 `json:"tps"`
+	PeakTps       map[string]tpsPeak `json:"peakTps"`
+}
+
+// apiTime formats the nano second timestamp in RFC 3339 UTC
+func apiTime(ts uint64) string {
+	return time.Unix(0, int64(ts)).UTC().Format(time.RFC3339)
+}
+
+func apiFail(c echo.Context, status int, code string, message string) error {
+	return c.JSON(status, &apiError{
+		Error: apiErrorBody{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+// initAPI registers the routes of the versioned REST API
+func (e *BlockExplorer) initAPI() {
+	e.route("GET", apiPrefix+"/blocks", &APIDoc{
+		Summary: "blocks from the cursor height downward",
+		Parameters: []APIParameter{
+			{Name: "cursor", Type: "integer", Description: "the height of the first block, the chain height as default"},
+			{Name: "limit", Type: "integer", Description: "the number of the blocks, 10 as default and 100 at most"},
+		},
+		Response: apiBlockList{},
+	}, e.apiBlocks)
+	e.route("GET", apiPrefix+"/blocks/:id", &APIDoc{
+		Summary:    "block by the height or the hash",
+		Parameters: []APIParameter{{Name: "id", In: "path", Required: true, Description: "block height or block hash"}},
+		Response:   apiBlock{},
+	}, e.apiBlock)
+	e.route("GET", apiPrefix+"/txs/:hash", &APIDoc{
+		Summary:    "transaction by the hash",
+		Parameters: []APIParameter{{Name: "hash", In: "path", Required: true}},
+		Response:   apiTx{},
+	}, e.apiTx)
+	e.route("GET", apiPrefix+"/chain/info", &APIDoc{
+		Summary:  "chain counters and tps",
+		Response: apiChainInfo{},
+	}, e.apiChainInfo)
+}
+
+func (e *BlockExplorer) apiBlockOf(height uint32) (*apiBlock, error) {
+	cd, err := e.Kernel.Provider().Data(height)
+	if err != nil {
+		return nil, err
+	}
+	b, err := e.Kernel.Block(height)
+	if err != nil {
+		return nil, err
+	}
+
+	ab := &apiBlock{
+		Height:         height,
+		Hash:           cd.Header.Hash().String(),
+		PrevHash:       cd.Header.PrevHash().String(),
+		ChainCoord:     b.Header.ChainCoord.String(),
+		Version:        int(cd.Header.Version()),
+		Timestamp:      apiTime(cd.Header.Timestamp()),
+		Formulator:     b.Header.Formulator.String(),
+		FormulatorName: e.nameOf(b.Header.Formulator),
+		TimeoutCount:   int(b.Header.TimeoutCount),
+		TxCount:        len(b.Body.Transactions),
+		Txs:            make([]string, 0, len(b.Body.Transactions)),
+	}
+	for _, t := range b.Body.Transactions {
+		ab.Txs = append(ab.Txs, t.Hash().String())
+	}
+	return ab, nil
+}
+
+// blockHeightByHash returns the height of the indexed block hash
+func (e *BlockExplorer) blockHeightByHash(h hash.Hash256) (height uint32, has bool, err error) {
+	err = e.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(blockHashKey(h))
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return nil
+			}
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		height = util.BytesToUint32(value)
+		has = true
+		return nil
+	})
+	return
+}
+
+// txPositionByHash returns the height and the index of the indexed transaction hash
+func (e *BlockExplorer) txPositionByHash(h hash.Hash256) (p txPosition, has bool, err error) {
+	err = e.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(txHashKey(h))
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return nil
+			}
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		if len(value) != 8 {
+			return nil
+		}
+		p.height = util.BytesToUint32(value[0:4])
+		p.index = util.BytesToUint32(value[4:8])
+		has = true
+		return nil
+	})
+	return
+}
+
+func (e *BlockExplorer) apiBlocks(c echo.Context) error {
+	top := e.Kernel.Provider().Height()
+	cursor := top
+	if s := c.QueryParam("cursor"); s != "" {
+		v, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return apiFail(c, http.StatusBadRequest, apiInvalidParameter, "cursor should be a block height")
+		}
+		if uint32(v) < cursor {
+			cursor = uint32(v)
+		}
+	}
+	limit := apiDefaultLimit
+	if s := c.QueryParam("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			return apiFail(c, http.StatusBadRequest, apiInvalidParameter, "limit should be a positive number")
+		}
+		limit = v
+	}
+	if limit > apiMaxLimit {
+		limit = apiMaxLimit
+	}
+
+	result := &apiBlockList{
+		Blocks: []apiBlock{},
+	}
+	height := cursor
+	for ; height > 0 && len(result.Blocks) < limit; height-- {
+		ab, err := e.apiBlockOf(height)
+		if err != nil {
+			return apiFail(c, http.StatusInternalServerError, apiInternal, err.Error())
+		}
+		result.Blocks = append(result.Blocks, *ab)
+	}
+	if height > 0 {
+		result.NextCursor = &height
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (e *BlockExplorer) apiBlock(c echo.Context) error {
+	id := c.Param("id")
+	var height uint32
+	if v, err := strconv.ParseUint(id, 10, 32); err == nil {
+		height = uint32(v)
+		if height == 0 || height > e.Kernel.Provider().Height() {
+			return apiFail(c, http.StatusNotFound, apiNotFound, "block is not exist")
+		}
+	} else {
+		h, err := hash.ParseHex(id)
+		if err != nil {
+			return apiFail(c, http.StatusBadRequest, apiInvalidParameter, "id should be a block height or a block hash")
+		}
+		v, has, err := e.blockHeightByHash(h)
+		if err != nil {
+			return apiFail(c, http.StatusInternalServerError, apiInternal, err.Error())
+		}
+		if !has {
+			return apiFail(c, http.StatusNotFound, apiNotFound, "block is not exist")
+		}
+		height = v
+	}
+
+	ab, err := e.apiBlockOf(height)
+	if err != nil {
+		return apiFail(c, http.StatusInternalServerError, apiInternal, err.Error())
+	}
+	return c.JSON(http.StatusOK, ab)
+}
+
+func (e *BlockExplorer) apiTx(c echo.Context) error {
+	h, err := hash.ParseHex(c.Param("hash"))
+	if err != nil {
+		return apiFail(c, http.StatusBadRequest, apiInvalidParameter, "hash should be a transaction hash")
+	}
+	p, has, err := e.txPositionByHash(h)
+	if err != nil {
+		return apiFail(c, http.StatusInternalServerError, apiInternal, err.Error())
+	}
+	if !has {
+		return apiFail(c, http.StatusNotFound, apiNotFound, "transaction is not exist")
+	}
+
+	b, err := e.Kernel.Block(p.height)
+	if err != nil {
+		return apiFail(c, http.StatusInternalServerError, apiInternal, err.Error())
+	}
+	if int(p.index) >= len(b.Body.Transactions) {
+		return apiFail(c, http.StatusNotFound, apiNotFound, "transaction is not exist")
+	}
+	t := b.Body.Transactions[p.index]
+	payload, err := t.MarshalJSON()
+	if err != nil {
+		return apiFail(c, http.StatusInternalServerError, apiInternal, err.Error())
+	}
+
+	at := &apiTx{
+		Hash:           t.Hash().String(),
+		Timestamp:      apiTime(t.Timestamp()),
+		BlockHeight:    p.height,
+		BlockHash:      b.Header.Hash().String(),
+		BlockTimestamp: apiTime(b.Header.Timestamp()),
+		Index:          p.index,
+		UTXO:           e.utxoDetailOf(t, p.height, uint16(p.index)),
+		Payload:        payload,
+	}
+	if name, err := e.Kernel.Transactor().NameByType(t.Type()); err == nil {
+		at.Type = name
+	}
+	if names := e.namesOf(e.txAddresses(t, p.height, p.index)); len(names) > 0 {
+		at.Names = names
+	}
+	return c.JSON(http.StatusOK, at)
+}
+
+// apiChainInfoOf returns the chain info of the published snapshot
+func (e *BlockExplorer) apiChainInfoOf() *apiChainInfo {
+	s := e.snapshot()
+	return &apiChainInfo{
+		Height:        e.Kernel.Provider().Height(),
+		IndexedHeight: s.IndexedHeight,
+		Formulators:   s.ChainInfo.Foumulators,
+		Transactions:  s.ChainInfo.Transactions,
+		Tps:           s.Tps,
+		PeakTps:       s.PeakTps,
+	}
+}
+
+func (e *BlockExplorer) apiChainInfo(c echo.Context) error {
+	return c.JSON(http.StatusOK, e.apiChainInfoOf())
+}
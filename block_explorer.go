@@ -2,21 +2,28 @@ package blockexplorer
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"html"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
 	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/framework/chain"
 	"github.com/labstack/echo"
 )
 
@@ -41,19 +48,34 @@ func init() {
 
 //Block explorer error list
 var (
-	ErrDbNotClear          = errors.New("Db is not clear")
-	ErrNotEnoughParameter  = errors.New("Not enough parameter")
-	ErrNotTransactionHash  = errors.New("This hash is not a transaction hash")
-	ErrNotBlockHash        = errors.New("This hash is not a block hash")
-	ErrInvalidHeightFormat = errors.New("Invalid height format")
+	ErrDbNotClear           = errors.New("Db is not clear")
+	ErrNotEnoughParameter   = errors.New("Not enough parameter")
+	ErrNotTransactionHash   = errors.New("This hash is not a transaction hash")
+	ErrNotBlockHash         = errors.New("This hash is not a block hash")
+	ErrInvalidHeightFormat  = errors.New("Invalid height format")
+	ErrNotContract          = errors.New("This address is not a contract")
+	ErrNotExistBlock        = errors.New("This block is not exist")
+	ErrInvalidAddressFormat = errors.New("Invalid address format")
+	ErrInvalidStartFormat   = errors.New("Invalid start format")
+	ErrUnknownDataOrder     = errors.New("Unknown data order")
+	ErrDuplicateDataOrder   = errors.New("Data order is already registered")
+	ErrUnknownLiveTopic     = errors.New("Unknown live feed topic")
+	ErrUnknownLiveAction    = errors.New("Unknown live feed action")
+	ErrTooManyLiveTopics    = errors.New("Too many live feed topics")
 )
 
+// BlockSource provides the blocks to the indexer, the kernel is used by the explorer
+// and the reindex reads the blocks from the kernel store without the kernel
+type BlockSource interface {
+	Provider() chain.Provider
+	Block(height uint32) (*block.Block, error)
+	Transactor() *data.Transactor
+}
+
 // BlockExplorer struct
 type BlockExplorer struct {
-	Kernel                 *kernel.Kernel
-	transactionCountList   []*countInfo
-	CurrentChainInfo       currentChainInfo
-	lastestTransactionList []txInfos
+	Kernel *kernel.Kernel
+	source BlockSource
 
 	db *badger.DB
 
@@ -62,9 +84,29 @@ type BlockExplorer struct {
 	e                *echo.Echo
 	webChecker       echo.MiddlewareFunc
 	assets           *fileAsset
+	dataHandlers     map[string]*DataHandler
 	dataHandlerPacks []DataHandlerPack
-
-	MaximumTps int
+	dataHandlersLock sync.RWMutex
+	apiDocsLock      sync.Mutex
+	apiRoutes        []apiRoute
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	workers   sync.WaitGroup
+
+	// the indexer state is owned by the indexing goroutine,
+	// the handlers read the published snapshot of it
+	chainState    currentChainInfo
+	tps           *tpsMeter
+	recents       recentFormulators
+	indexWorkers  int32
+	indexedHeight uint32
+	rollbacks     int
+	lastRollback  *rollbackInfo
+	blockEvent    chan struct{}
+	feed          *liveFeed
+
+	state atomic.Value
 }
 
 type countInfo struct {
@@ -74,6 +116,50 @@ type countInfo struct {
 
 //NewBlockExplorer TODO
 func NewBlockExplorer(dbPath string, Kernel *kernel.Kernel, resourcePath string) (*BlockExplorer, error) {
+	return NewBlockExplorerWithGenesis(dbPath, Kernel, resourcePath, nil)
+}
+
+// NewBlockExplorerWithGenesis creates the explorer and indexes the genesis context data before the workers start,
+// so the backfill never writes the index at the same time
+func NewBlockExplorerWithGenesis(dbPath string, Kernel *kernel.Kernel, resourcePath string, GenesisContextData *data.ContextData) (*BlockExplorer, error) {
+	if err := recoverReindex(dbPath); err != nil {
+		return nil, err
+	}
+	e, err := openBlockExplorer(dbPath, Kernel, resourcePath, GenesisContextData)
+	if err != nil {
+		return nil, err
+	}
+	e.Kernel = Kernel
+
+	e.workers.Add(2)
+	go func() {
+		defer e.workers.Done()
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-e.closeCh:
+				return
+			case <-ticker.C:
+			}
+		again:
+			if err := e.db.RunValueLogGC(0.7); err != nil {
+			} else {
+				goto again
+			}
+		}
+	}()
+	go func() {
+		defer e.workers.Done()
+		e.runBackfill()
+	}()
+
+	return e, nil
+}
+
+// openBlockExplorer opens the db and loads the indexed state without starting the workers
+// the genesis context data is indexed when it is given
+func openBlockExplorer(dbPath string, source BlockSource, resourcePath string, GenesisContextData *data.ContextData) (*BlockExplorer, error) {
 	opts := badger.DefaultOptions
 	opts.Dir = dbPath
 	opts.ValueDir = dbPath
@@ -98,29 +184,25 @@ func NewBlockExplorer(dbPath string, Kernel *kernel.Kernel, resourcePath string)
 		}
 	}
 
-	ticker := time.NewTicker(5 * time.Minute)
-	go func() {
-		for range ticker.C {
-		again:
-			if err := db.RunValueLogGC(0.7); err != nil {
-			} else {
-				goto again
-			}
-		}
-	}()
+	if err := runMigrations(db); err != nil {
+		db.Close()
+		return nil, err
+	}
 
 	e := &BlockExplorer{
-		Kernel:                 Kernel,
-		transactionCountList:   []*countInfo{},
-		lastestTransactionList: []txInfos{},
-		db:               db,
-		resourcePath:     resourcePath,
-		assets:           NewFileAsset(Assets, resourcePath),
-		dataHandlerPacks: []DataHandlerPack{},
+		source:       source,
+		db:           db,
+		resourcePath: resourcePath,
+		assets:       NewFileAsset(Assets, resourcePath),
+		dataHandlers: map[string]*DataHandler{},
+		blockEvent:   make(chan struct{}, 1),
+		tps:          newTpsMeter(),
+		closeCh:      make(chan struct{}),
+		feed:         newLiveFeed(),
 	}
 
 	if err := e.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(blockChainInfoBytes)
+		item, err := txn.Get(blockChainInfoKey)
 		if err != nil {
 			if err != badger.ErrKeyNotFound {
 				return err
@@ -131,251 +213,162 @@ func NewBlockExplorer(dbPath string, Kernel *kernel.Kernel, resourcePath string)
 				return err
 			}
 			buf := bytes.NewBuffer(value)
-			e.CurrentChainInfo.ReadFrom(buf)
+			e.chainState.ReadFrom(buf)
+		}
+
+		if err := e.loadTpsPeaks(txn); err != nil {
+			return err
 		}
 
-		item, err = txn.Get(MaximumTpsBytes)
+		item, err = txn.Get(indexCursorKey)
 		if err != nil {
 			if err != badger.ErrKeyNotFound {
 				return err
 			}
+			// the db of the old version has no cursor and only the hashes and the formulator counts,
+			// the history is indexed again from the genesis to fill the other indexes
+			e.indexedHeight = 0
 		} else {
 			value, err := item.ValueCopy(nil)
 			if err != nil {
 				return err
 			}
-			tps := util.BytesToUint32(value)
-			e.MaximumTps = int(tps)
+			e.indexedHeight = util.BytesToUint32(value)
+		}
+
+		// the total is derived from the transaction sequence which is written with the blocks
+		if _, err := txn.Get(txCountKey); err == nil {
+			count, err := getTxCount(txn)
+			if err != nil {
+				return err
+			}
+			e.chainState.Transactions = int(count)
 		}
 
 		return nil
 	}); err != nil {
+		db.Close()
 		return nil, ErrDbNotClear
 	}
-
-	currHeight := e.Kernel.Provider().Height()
-
-	for i := currHeight; i > 0; i-- {
-		if len(e.lastestTransactionList) >= 500 {
-			break
-		}
-		b, err := e.Kernel.Block(i)
-		if err != nil {
-			continue
-		}
-		txs := b.Body.Transactions
-		for _, tx := range txs {
-			name, _ := e.Kernel.Transactor().NameByType(tx.Type())
-			e.lastestTransactionList = append(e.lastestTransactionList, txInfos{
-				TxHash:    tx.Hash().String(),
-				BlockHash: b.Header.Hash().String(),
-				ChainID:   b.Header.ChainCoord.String(),
-				Time:      tx.Timestamp(),
-				TxType:    name,
-			})
+	if GenesisContextData != nil {
+		if err := e.IndexGenesis(GenesisContextData); err != nil {
+			db.Close()
+			return nil, err
 		}
 	}
-
-	go func(e *BlockExplorer) {
-		for {
-			time.Sleep(time.Second)
-			e.updateChainInfoCount()
-		}
-	}(e)
+	e.rebuildTps()
+	e.rebuildRecentFormulators()
+	e.publish()
+	e.initDataHandlers()
 
 	return e, nil
 }
 
+// Close stops the workers and closes the db
+func (e *BlockExplorer) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.closeCh)
+	})
+	e.workers.Wait()
+	e.feed.closeAll()
+	return e.db.Close()
+}
+
 // AddAssets is add assets to obj
 func (e *BlockExplorer) AddAssets(assets http.FileSystem) {
 	e.assets.AddAssets(assets)
 }
 
-var blockChainInfoBytes = []byte("blockChainInfo")
-var MaximumTpsBytes = []byte("MaximumTps")
-
-// LastestTransactionLen is returned length of lastest txs
+// LastestTransactionLen is returned length of indexed txs
 func (e *BlockExplorer) LastestTransactionLen() int {
-	return len(e.lastestTransactionList)
+	return int(e.TransactionCount())
 }
-func (e *BlockExplorer) updateChainInfoCount() error {
-	currHeight := e.Kernel.Provider().Height()
-
-	e.CurrentChainInfo.currentTransactions = 0
-	e.CurrentChainInfo.Foumulators = e.Kernel.CandidateCount()
-	minHeight := e.CurrentChainInfo.Blocks
-	e.CurrentChainInfo.Blocks = currHeight
-
-	newTxs := []txInfos{}
-	newTxCountInfos := []*countInfo{}
-	for i := currHeight - currHeight%2; i > minHeight && i >= 0; i -= 2 {
-		height := i
-		b, err := e.Kernel.Block(height)
-		if err != nil {
-			continue
-		}
-		height2 := i - 1
-		b2, err := e.Kernel.Block(height2)
-		if err != nil {
-			continue
-		}
-		e.CurrentChainInfo.currentTransactions += len(b.Body.Transactions)
-		e.CurrentChainInfo.currentTransactions += len(b2.Body.Transactions)
-
-		tps := len(b.Body.Transactions) + len(b2.Body.Transactions)
-		if e.MaximumTps < tps {
-			e.MaximumTps = tps
-		}
-
-		if len(newTxCountInfos) < 200 {
-			newTxCountInfos = append(newTxCountInfos, &countInfo{
-				Time:  int64(b.Header.Timestamp()),
-				Count: tps,
-			})
-		}
-
-		txs := b.Body.Transactions
-		for _, tx := range txs {
-			name, _ := e.Kernel.Transactor().NameByType(tx.Type())
-			if len(newTxs) > 500 {
-				break
-			}
-			newTxs = append(newTxs, txInfos{
-				TxHash:    tx.Hash().String(),
-				BlockHash: b.Header.Hash().String(),
-				ChainID:   b.Header.ChainCoord.String(),
-				Time:      tx.Timestamp(),
-				TxType:    name,
-			})
-		}
 
-		txs = b2.Body.Transactions
-		for _, tx := range txs {
-			name, _ := e.Kernel.Transactor().NameByType(tx.Type())
-			if len(newTxs) > 500 {
-				break
-			}
-			newTxs = append(newTxs, txInfos{
-				TxHash:    tx.Hash().String(),
-				BlockHash: b.Header.Hash().String(),
-				ChainID:   b.Header.ChainCoord.String(),
-				Time:      tx.Timestamp(),
-				TxType:    name,
-			})
-		}
+func (e *BlockExplorer) updateChainInfoCount() {
+	e.chainState.Foumulators = e.Kernel.CandidateCount()
+	e.chainState.Blocks = e.Kernel.Provider().Height()
+	e.publish()
+}
 
-		e.updateBlock(b, height)
-		e.updateBlock(b2, height2)
+func (e *BlockExplorer) updateBlock(txn *badger.Txn, b *block.Block, height uint32, tps *tpsMeter) error {
+	// the replayed block is already indexed, indexing it again would count it twice
+	if indexed, err := isIndexedBlock(txn, height, b.Header.Hash()); err != nil {
+		return err
+	} else if indexed {
+		return nil
 	}
-	e.CurrentChainInfo.Blocks = currHeight
-
-	if len(newTxs) > 0 {
-		e.lastestTransactionList = append(newTxs, e.lastestTransactionList...)
-		if len(e.lastestTransactionList) > 500 {
-			e.lastestTransactionList = e.lastestTransactionList[:500]
-		}
+	if err := unindexOldBlock(txn, b, height); err != nil {
+		return err
 	}
 
-	if len(newTxCountInfos) > 0 {
-		e.transactionCountList = append(newTxCountInfos, e.transactionCountList...)
-		if len(e.transactionCountList) > 500 {
-			e.transactionCountList = e.transactionCountList[:500]
-		}
+	it := newIndexTxn(txn)
+	//start block hash update
+	err := e.updateHashs(it, b, height)
+	if err != nil {
+		return err
 	}
-
-	e.CurrentChainInfo.Transactions += e.CurrentChainInfo.currentTransactions
-
-	if err := e.db.Update(func(txn *badger.Txn) error {
-		buf := &bytes.Buffer{}
-		_, err := e.CurrentChainInfo.WriteTo(buf)
-		if err != nil {
-			return err
-		}
-		txn.Set(blockChainInfoBytes, buf.Bytes())
-		txn.Set(MaximumTpsBytes, util.Uint32ToBytes(uint32(e.MaximumTps)))
-		return nil
-	}); err != nil {
+	//end block hash update
+	if err := e.updateTxSequence(it, b, height); err != nil {
 		return err
 	}
-
-	return nil
-}
-
-func (e *BlockExplorer) updateBlock(b *block.Block, height uint32) error {
-	if err := e.db.Update(func(txn *badger.Txn) error {
-		//start block hash update
-		err := e.updateHashs(txn, height)
-		if err != nil {
-			return err
-		}
-		//end block hash update
-		return nil
-	}); err != nil {
+	if err := e.updateBlockTypes(it, b, height); err != nil {
 		return err
 	}
-	return nil
-}
-
-func (e *BlockExplorer) updateHashs(txn *badger.Txn, height uint32) error {
-	b, err := e.Kernel.Block(height)
-	if err != nil {
+	if err := e.updateChainStats(it, b, height); err != nil {
+		return err
+	}
+	if err := e.updateRollups(it, b); err != nil {
+		return err
+	}
+	if err := e.updateUTXOs(it, b, height); err != nil {
 		return err
 	}
+	if err := e.updateContracts(it, b, height); err != nil {
+		return err
+	}
+	if err := e.updateNames(it, b, height); err != nil {
+		return err
+	}
+	if err := updateTpsPeaks(it, tps, b, height); err != nil {
+		return err
+	}
+	it.undo.TxCount = uint32(len(b.Body.Transactions))
+	return commitUndo(it, height, b.Header.Hash())
+}
+
+func (e *BlockExplorer) updateHashs(it *indexTxn, b *block.Block, height uint32) error {
 	value := util.Uint32ToBytes(height)
 
-	h := b.Header.Hash().String()
-	if err := txn.Set([]byte(h), value); err != nil {
+	if err := it.Set(blockHashKey(b.Header.Hash()), value); err != nil {
 		return err
 	}
 
-	formulatorAddr := []byte("formulator" + b.Header.Formulator.String())
-	item, err := txn.Get(formulatorAddr)
-	if err != nil {
-		if err != badger.ErrKeyNotFound {
-			return err
-		}
-		txn.Set(formulatorAddr, util.Uint32ToBytes(1))
-	} else {
-		value, err := item.ValueCopy(nil)
-		if err != nil {
-			return err
-		}
-		height := util.BytesToUint32(value)
-		txn.Set(formulatorAddr, util.Uint32ToBytes(height+1))
+	if err := e.updateFormulator(it, b, height); err != nil {
+		return err
 	}
 
 	txs := b.Body.Transactions
 	for i, tx := range txs {
 		h := tx.Hash()
 		v := append(value, util.Uint32ToBytes(uint32(i))...)
-		if err := txn.Set(h[:], v); err != nil {
+		if err := it.Set(txHashKey(h), v); err != nil {
 			return err
 		}
 	}
+
+	if err := e.updateAddressTxs(it, b, height); err != nil {
+		return err
+	}
 	return nil
 }
 
 // GetBlockCount return block height
 func (e *BlockExplorer) GetBlockCount(formulatorAddr string) (height uint32) {
-	formulatorKey := []byte("formulator" + formulatorAddr)
-	e.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(formulatorKey)
-		if err != nil {
-			if err != badger.ErrKeyNotFound {
-				return err
-			}
-			height = 0
-		} else {
-			value, err := item.ValueCopy(nil)
-			if err != nil {
-				return err
-			}
-			height = util.BytesToUint32(value)
-		}
-
-		return nil
-	})
-	return
+	addr, err := common.ParseAddress(formulatorAddr)
+	if err != nil {
+		return 0
+	}
+	return e.formulatorRecordOf(addr).Blocks
 }
 
 // InitURL is initialization urls
@@ -384,6 +377,8 @@ func (e *BlockExplorer) InitURL() {
 	e.e = echo.New()
 	web := NewWebServer(e.e, e.assets, e.resourcePath)
 	e.e.Renderer = web
+	e.e.HTTPErrorHandler = e.httpErrorHandler
+	e.e.Use(e.recoverPanic)
 
 	ec := NewExplorerController(e.db, e)
 
@@ -398,65 +393,109 @@ func (e *BlockExplorer) InitURL() {
 	}
 
 	e.e.Any("/data/:order", e.dataHandler)
-	e.e.GET("/", func(c echo.Context) error {
+	e.initAPI()
+	e.e.GET(openapiPath, e.openapiHandler)
+	e.route("GET", "/ws", &APIDoc{
+		Summary: "websocket live feed of the topics blocks, txs, chainInfo, txs:type:{name} and txs:address:{address}, the transaction topics send the transactions of a block in a message, the blocks are sent when the index reaches the tip, the messages are the subscribe or unsubscribe requests",
+		Parameters: []APIParameter{
+			{Name: "topics", Description: "comma separated topics subscribed on the connection"},
+		},
+		Response: liveMessage{},
+	}, e.liveHandler)
+	e.route("GET", "/", pageDoc("dashboard page"), func(c echo.Context) error {
+		s := e.snapshot()
 		args := map[string]string{
-			"MaximumTps": fmt.Sprintln(e.MaximumTps),
-		}
-		err := c.Render(http.StatusOK, "index.html", args)
-		if err != nil {
-			log.Println(err)
+			"Tps":     fmt.Sprintf("%.2f", s.Tps["1m"]),
+			"PeakTps": fmt.Sprintf("%.2f", s.PeakTps["1m"].Tps),
 		}
-		return err
+		return c.Render(http.StatusOK, "index.html", args)
 	}, e.webChecker)
-	e.e.GET("/blocks", func(c echo.Context) error {
+	e.route("GET", "/blocks", pageDoc("block list page"), func(c echo.Context) error {
 		args, err := ec.Blocks(c.Request())
 		if err != nil {
-			log.Println(err)
+			return err
 		}
-		err = c.Render(http.StatusOK, "blocks.html", args)
+		return c.Render(http.StatusOK, "blocks.html", args)
+	}, e.webChecker)
+	e.route("GET", "/blockDetail", pageDoc("block detail page by the height or the hash", "height", "hash"), func(c echo.Context) error {
+		args, err := ec.BlockDetail(c.Request())
 		if err != nil {
-			log.Println(err)
+			return err
 		}
-		return err
+		return c.Render(http.StatusOK, "blockDetail.html", args)
 	}, e.webChecker)
-	e.e.GET("/blockDetail", func(c echo.Context) error {
-		args, err := ec.BlockDetail(c.Request())
+	e.route("GET", "/transactions", pageDoc("transaction list page"), func(c echo.Context) error {
+		args, err := ec.Transactions(c.Request())
 		if err != nil {
-			log.Println(err)
+			return err
+		}
+		return c.Render(http.StatusOK, "transactions.html", args)
+	}, e.webChecker)
+	e.route("GET", "/transactionDetail", pageDoc("transaction detail page", "hash"), func(c echo.Context) error {
+		args, err := ec.TransactionDetail(c.Request())
+		if err == ErrNotTransactionHash {
+			// the hash can be a block hash
+			return c.Redirect(http.StatusFound, "/search?q="+url.QueryEscape(c.QueryParam("hash")))
 		}
-		err = c.Render(http.StatusOK, "blockDetail.html", args)
 		if err != nil {
-			log.Println(err)
+			return err
 		}
-		return err
+		return c.Render(http.StatusOK, "transactionDetail.html", args)
 	}, e.webChecker)
-	e.e.GET("/transactions", func(c echo.Context) error {
-		args, err := ec.Transactions(c.Request())
+	e.route("GET", "/formulators", pageDoc("formulator leaderboard page", "sort", "order"), func(c echo.Context) error {
+		args, err := ec.Formulators(c.Request())
 		if err != nil {
-			log.Println(err)
+			return err
 		}
-		err = c.Render(http.StatusOK, "transactions.html", args)
+		return c.Render(http.StatusOK, "formulators.html", args)
+	}, e.webChecker)
+	e.route("GET", "/account", pageDoc("account page", "addr"), func(c echo.Context) error {
+		args, err := ec.Account(c.Request())
 		if err != nil {
-			log.Println(err)
+			return err
 		}
-		return err
+		return c.Render(http.StatusOK, "account.html", args)
 	}, e.webChecker)
-	e.e.GET("/transactionDetail", func(c echo.Context) error {
-		args, err := ec.TransactionDetail(c.Request())
+	e.route("GET", "/contract", pageDoc("contract list page or the contract page of the address", "addr"), func(c echo.Context) error {
+		args, err := ec.Contract(c.Request())
 		if err != nil {
-			log.Println(err)
+			return err
 		}
-		err = c.Render(http.StatusOK, "transactionDetail.html", args)
+		return c.Render(http.StatusOK, "contract.html", args)
+	}, e.webChecker)
+	e.route("GET", "/search", pageDoc("search which redirects to the single match", "q"), func(c echo.Context) error {
+		result := e.search(c.QueryParam("q"))
+		if len(result.Matches) == 1 {
+			return c.Redirect(http.StatusFound, result.Matches[0].URL)
+		}
+		j, _ := json.Marshal(result)
+		// the pages are rendered by text/template, so the query is escaped here
+		return c.Render(http.StatusOK, "search.html", map[string]string{
+			"query":      html.EscapeString(result.Query),
+			"searchData": string(j),
+		})
+	}, e.webChecker)
+	e.route("GET", "/address", pageDoc("transaction history page of the address", "addr"), func(c echo.Context) error {
+		args, err := ec.Address(c.Request())
 		if err != nil {
-			log.Println(err)
+			return err
 		}
-		return err
+		return c.Render(http.StatusOK, "address.html", args)
 	}, e.webChecker)
 
 }
 
-// AddURL add homepage url
-func (e *BlockExplorer) AddURL(url string, method string, handler func(c echo.Context) error) {
+// route registers the handler of the built-in route with the description of it in the OpenAPI document
+func (e *BlockExplorer) route(method string, path string, doc *APIDoc, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) {
+	e.e.Add(method, path, handler, middleware...)
+	e.addAPIDoc(method, path, doc)
+}
+
+// AddURL add homepage url, the doc is published in the OpenAPI document when it is given
+func (e *BlockExplorer) AddURL(url string, method string, handler func(c echo.Context) error, docs ...*APIDoc) {
+	for _, doc := range docs {
+		e.addAPIDoc(method, url, doc)
+	}
 	switch method {
 	case "CONNECT":
 		e.e.CONNECT(url, handler, e.webChecker)
@@ -488,44 +527,3 @@ func (e *BlockExplorer) StartExplorer(port int) {
 	}
 	e.e.Start(":" + strconv.Itoa(port))
 }
-
-// AddDataHandler add data handler
-func (e *BlockExplorer) AddDataHandler(d DataHandlerPack) {
-	e.dataHandlerPacks = append(e.dataHandlerPacks, d)
-}
-
-// DataHandlerPack interface of handler
-type DataHandlerPack interface {
-	DataHandler(c echo.Context) (interface{}, error)
-}
-
-func (e *BlockExplorer) dataHandler(c echo.Context) error {
-	order := c.Param("order")
-	var result interface{}
-
-	switch order {
-	case "transactions.data":
-		result = e.transactions()
-	case "currentChainInfo.data":
-		result = e.CurrentChainInfo
-	case "lastestBlocks.data":
-		result = e.lastestBlocks()
-	case "lastestTransactions.data":
-		result = e.lastestTransactions()
-	case "paginationBlocks.data":
-		startStr := c.QueryParam("start")
-		result = e.paginationBlocks(startStr)
-	case "paginationTxs.data":
-		startStr := c.QueryParam("start")
-		result = e.paginationTxs(startStr)
-	default:
-		for _, v := range e.dataHandlerPacks {
-			var err error
-			result, err = v.DataHandler(c)
-			if err == nil {
-				break
-			}
-		}
-	}
-	return c.JSON(http.StatusOK, result)
-}
@@ -4,33 +4,66 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/fletaio/common"
 	"github.com/fletaio/core/block"
 )
 
-func (e *BlockExplorer) transactions() []*countInfo {
-	return e.transactionCountList
+// parseStart parses the start of the page, the first page is used when it is empty
+func parseStart(startStr string) (int, error) {
+	if startStr == "" {
+		return 0, nil
+	}
+	start, err := strconv.Atoi(startStr)
+	if err != nil || start < 0 {
+		return 0, ErrInvalidStartFormat
+	}
+	return start, nil
+}
+
+// parseAddressParam parses the address parameter which is required
+func parseAddressParam(addrStr string) (common.Address, error) {
+	if addrStr == "" {
+		return common.Address{}, ErrNotEnoughParameter
+	}
+	addr, err := common.ParseAddress(addrStr)
+	if err != nil {
+		return common.Address{}, ErrInvalidAddressFormat
+	}
+	return addr, nil
+}
+
+type chainInfoData struct {
+	currentChainInfo
+	Tps     map[string]float64 `json:"tps"`
+	PeakTps map[string]tpsPeak `json:"peakTps"`
 }
-func (e *BlockExplorer) chainInfo() currentChainInfo {
-	return e.CurrentChainInfo
+
+func (e *BlockExplorer) chainInfo() chainInfoData {
+	s := e.snapshot()
+	return chainInfoData{
+		currentChainInfo: s.ChainInfo,
+		Tps:              s.Tps,
+		PeakTps:          s.PeakTps,
+	}
 }
 
 type typePerBlock struct {
 	BlockTime uint64 `json:"blockTime"`
 	Symbol    string `json:"symbol"`
 	TxCount   string `json:"txCount"`
-	// Types     map[string]int `json:"types"`
 }
 
 type blockInfos struct {
-	BlockHeight uint32   `json:"Block Height"`
-	BlockHash   string   `json:"Block Hash"`
-	Time        string   `json:"Time"`
-	Status      string   `json:"Status"`
-	Txs         string   `json:"Txs"`
-	Formulator  string   `json:"Formulator"`
-	Msg         string   `json:"Msg"`
-	Signs       []string `json:"Signs"`
-	BlockCount  uint32   `json:"BlockCount"`
+	BlockHeight    uint32   `json:"Block Height"`
+	BlockHash      string   `json:"Block Hash"`
+	Time           string   `json:"Time"`
+	Status         string   `json:"Status"`
+	Txs            string   `json:"Txs"`
+	Formulator     string   `json:"Formulator"`
+	FormulatorName string   `json:"FormulatorName"`
+	Msg            string   `json:"Msg"`
+	Signs          []string `json:"Signs"`
+	BlockCount     uint32   `json:"BlockCount"`
 }
 type blockInfosCase struct {
 	ITotalRecords        int          `json:"iTotalRecords"`
@@ -40,51 +73,58 @@ type blockInfosCase struct {
 	AaData               []blockInfos `json:"aaData"`
 }
 
+// blockInfoOf returns the row of the recent blocks with the signatures of the observers
+func (e *BlockExplorer) blockInfoOf(height uint32) (blockInfos, error) {
+	b, err := e.Kernel.Block(height)
+	if err != nil {
+		return blockInfos{}, err
+	}
+	cd, err := e.Kernel.Provider().Data(height)
+	if err != nil {
+		return blockInfos{}, err
+	}
+	status := 1
+	if b.Header.TimeoutCount > 0 {
+		status = 2
+	}
+
+	bs := block.Signed{
+		HeaderHash:         cd.Header.Hash(),
+		GeneratorSignature: cd.Signatures[0],
+	}
+	Signs := []string{
+		cd.Signatures[1].String(),
+		cd.Signatures[2].String(),
+		cd.Signatures[3].String(),
+	}
+
+	tm := time.Unix(int64(cd.Header.Timestamp()/uint64(time.Second)), 0)
+
+	return blockInfos{
+		BlockHeight:    height,
+		BlockHash:      cd.Header.Hash().String(),
+		Time:           tm.Format("2006-01-02 15:04:05"),
+		Status:         strconv.Itoa(status),
+		Txs:            strconv.Itoa(len(b.Body.Transactions)),
+		Formulator:     b.Header.Formulator.String(),
+		FormulatorName: e.nameOf(b.Header.Formulator),
+		Msg:            bs.Hash().String(),
+		Signs:          Signs,
+		BlockCount:     e.GetBlockCount(b.Header.Formulator.String()),
+	}, nil
+}
+
 func (e *BlockExplorer) lastestBlocks() (result blockInfosCase) {
 	currHeight := e.Kernel.Provider().Height()
 
 	result.AaData = []blockInfos{}
 
 	for i := currHeight; i > 0 && i > currHeight-8; i-- {
-		b, err := e.Kernel.Block(i)
+		info, err := e.blockInfoOf(i)
 		if err != nil {
 			continue
 		}
-
-		b.Header.Hash().String()
-
-		cd, err := e.Kernel.Provider().Data(i)
-		if err != nil {
-			continue
-		}
-		status := 1
-		if b.Header.TimeoutCount > 0 {
-			status = 2
-		}
-
-		bs := block.Signed{
-			HeaderHash:         cd.Header.Hash(),
-			GeneratorSignature: cd.Signatures[0],
-		}
-		Signs := []string{
-			cd.Signatures[1].String(),
-			cd.Signatures[2].String(),
-			cd.Signatures[3].String(),
-		}
-
-		tm := time.Unix(int64(cd.Header.Timestamp()/uint64(time.Second)), 0)
-
-		result.AaData = append(result.AaData, blockInfos{
-			BlockHeight: i,
-			BlockHash:   cd.Header.Hash().String(),
-			Time:        tm.Format("2006-01-02 15:04:05"),
-			Status:      strconv.Itoa(status),
-			Txs:         strconv.Itoa(len(b.Body.Transactions)),
-			Formulator:  b.Header.Formulator.String(),
-			Msg:         bs.Hash().String(),
-			Signs:       Signs,
-			BlockCount:  e.GetBlockCount(b.Header.Formulator.String()),
-		})
+		result.AaData = append(result.AaData, info)
 	}
 
 	result.ITotalRecords = len(result.AaData)
@@ -102,10 +142,7 @@ type txInfos struct {
 }
 
 func (e *BlockExplorer) lastestTransactions() []txInfos {
-	if len(e.lastestTransactionList) < 8 {
-		return e.lastestTransactionList[0:len(e.lastestTransactionList)]
-	}
-	return e.lastestTransactionList[0:8]
+	return e.txs(0, 8)
 }
 
 func (e *BlockExplorer) blocks(start int, currHeight uint32) []blockInfos {
@@ -129,21 +166,26 @@ func (e *BlockExplorer) blocks(start int, currHeight uint32) []blockInfos {
 		tm := time.Unix(int64(cd.Header.Timestamp()/uint64(time.Second)), 0)
 
 		aaData = append(aaData, blockInfos{
-			BlockHeight: i,
-			BlockHash:   cd.Header.Hash().String(),
-			Time:        tm.Format("2006-01-02 15:04:05"),
-			Status:      strconv.Itoa(status),
-			Txs:         strconv.Itoa(len(b.Body.Transactions)),
+			BlockHeight:    i,
+			BlockHash:      cd.Header.Hash().String(),
+			Time:           tm.Format("2006-01-02 15:04:05"),
+			Status:         strconv.Itoa(status),
+			Txs:            strconv.Itoa(len(b.Body.Transactions)),
+			Formulator:     b.Header.Formulator.String(),
+			FormulatorName: e.nameOf(b.Header.Formulator),
 		})
 	}
 
 	return aaData
 }
 
-func (e *BlockExplorer) paginationBlocks(startStr string) (result blockInfosCase) {
-	start, err := strconv.Atoi(startStr)
+func (e *BlockExplorer) paginationBlocks(startStr string) (result blockInfosCase, err error) {
+	if startStr == "" {
+		return result, ErrNotEnoughParameter
+	}
+	start, err := parseStart(startStr)
 	if err != nil {
-		return
+		return result, err
 	}
 	currHeight := e.Kernel.Provider().Height()
 
@@ -164,23 +206,22 @@ type txInfosCase struct {
 }
 
 func (e *BlockExplorer) txs(start int, length int) []txInfos {
-	max := start + length
-	if max > len(e.lastestTransactionList) {
-		max = len(e.lastestTransactionList)
-	}
-
-	return e.lastestTransactionList[start:max]
+	return e.txInfosByPosition(e.txSequenceList(start, length))
 }
 
-func (e *BlockExplorer) paginationTxs(startStr string) (result txInfosCase) {
-	start, err := strconv.Atoi(startStr)
+func (e *BlockExplorer) paginationTxs(startStr string) (result txInfosCase, err error) {
+	if startStr == "" {
+		return result, ErrNotEnoughParameter
+	}
+	start, err := parseStart(startStr)
 	if err != nil {
-		return
+		return result, err
 	}
 	length := 10
 
-	result.ITotalRecords = len(e.lastestTransactionList)
-	result.ITotalDisplayRecords = len(e.lastestTransactionList)
+	total := int(e.TransactionCount())
+	result.ITotalRecords = total
+	result.ITotalDisplayRecords = total
 
 	result.AaData = e.txs(start, length)
 
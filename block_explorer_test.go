@@ -22,17 +22,20 @@ import (
 )
 
 func TestBlockExplorer_startExplorer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the web server runs until the process is killed")
+	}
 
 	basePath := "./test/"
 	os.RemoveAll(basePath)
 
 	kn := newKernel(basePath)
 
-	e, err := NewBlockExplorer(basePath, kn)
+	e, err := NewBlockExplorer(basePath, kn, "./webfiles")
 	if err != nil {
 		panic(err)
 	}
-	e.StartExplorer()
+	e.StartExplorer(58001)
 }
 
 func newKernel(basePath string) *kernel.Kernel {
@@ -0,0 +1,178 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+)
+
+// chainRecord is the statistics of the blocks of a chain coordinate
+type chainRecord struct {
+	Height        uint32
+	Blocks        uint32
+	Txs           uint64
+	LastBlockTime uint64
+	Formulators   uint32
+}
+
+// WriteTo is a serialization function
+func (cr *chainRecord) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint32(w, cr.Height); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, cr.Blocks); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint64(w, cr.Txs); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint64(w, cr.LastBlockTime); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, cr.Formulators); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (cr *chainRecord) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		cr.Height = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		cr.Blocks = v
+	}
+	if v, n, err := util.ReadUint64(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		cr.Txs = v
+	}
+	if v, n, err := util.ReadUint64(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		cr.LastBlockTime = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		cr.Formulators = v
+	}
+	return read, nil
+}
+
+// updateChainStats adds the block to the record of its chain coordinate
+func (e *BlockExplorer) updateChainStats(it *indexTxn, b *block.Block, height uint32) error {
+	coord := b.Header.ChainCoord
+	cr := &chainRecord{}
+	value, err := it.Get(chainKey(coord))
+	if err != nil {
+		return err
+	}
+	if value != nil {
+		if _, err := cr.ReadFrom(bytes.NewReader(value)); err != nil {
+			return err
+		}
+	}
+	cr.Height = height
+	cr.Blocks++
+	cr.Txs += uint64(len(b.Body.Transactions))
+	cr.LastBlockTime = b.Header.Timestamp()
+
+	fkey := chainFormulatorKey(coord, b.Header.Formulator)
+	if has, err := it.Get(fkey); err != nil {
+		return err
+	} else if has == nil {
+		if err := it.Set(fkey, []byte{}); err != nil {
+			return err
+		}
+		cr.Formulators++
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := cr.WriteTo(buf); err != nil {
+		return err
+	}
+	return it.Set(chainKey(coord), buf.Bytes())
+}
+
+type chainInfos struct {
+	Chain         string `json:"Chain"`
+	Height        uint32 `json:"Height"`
+	Blocks        uint32 `json:"Blocks"`
+	Txs           uint64 `json:"Txs"`
+	LastBlockTime string `json:"LastBlockTime"`
+	Formulators   uint32 `json:"Formulators"`
+}
+
+type chainInfosCase struct {
+	ITotalRecords        int          `json:"iTotalRecords"`
+	ITotalDisplayRecords int          `json:"iTotalDisplayRecords"`
+	SEcho                int          `json:"sEcho"`
+	SColumns             string       `json:"sColumns"`
+	AaData               []chainInfos `json:"aaData"`
+}
+
+// chainInfoTable returns a row per the chain coordinate
+func (e *BlockExplorer) chainInfoTable() (result chainInfosCase) {
+	result.AaData = []chainInfos{}
+
+	e.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		prefix := []byte{chainPrefix}
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			key := item.Key()[len(prefix):]
+			coord := common.NewCoordinate(keyToUint32(key[0:4]), keyToUint16(key[4:6]))
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			cr := &chainRecord{}
+			if _, err := cr.ReadFrom(bytes.NewReader(value)); err != nil {
+				continue
+			}
+			tm := time.Unix(int64(cr.LastBlockTime/uint64(time.Second)), 0)
+			result.AaData = append(result.AaData, chainInfos{
+				Chain:         coord.String(),
+				Height:        cr.Height,
+				Blocks:        cr.Blocks,
+				Txs:           cr.Txs,
+				LastBlockTime: tm.Format("2006-01-02 15:04:05"),
+				Formulators:   cr.Formulators,
+			})
+		}
+		return nil
+	})
+
+	result.ITotalRecords = len(result.AaData)
+	result.ITotalDisplayRecords = len(result.AaData)
+	return
+}
@@ -28,13 +28,14 @@ import (
 
 // Config is a configuration for the cmd
 type Config struct {
-	SeedNodes    []string
-	ObserverKeys []string
-	Port         int
-	APIPort      int
-	ExplorerPort int
-	StoreRoot    string
-	ForceRecover bool
+	SeedNodes            []string
+	ObserverKeys         []string
+	Port                 int
+	APIPort              int
+	ExplorerPort         int
+	ExplorerIndexWorkers int
+	StoreRoot            string
+	ForceRecover         bool
 }
 
 func main() {
@@ -80,9 +81,12 @@ func main() {
 	}()
 	defer cm.CloseAll()
 
+	// the reindex only reads the blocks of the kernel store, so the store is never recovered and the kernel is not created
+	reindexMode := len(os.Args) > 1 && os.Args[1] == "reindex"
+
 	var ks *kernel.Store
-	if s, err := kernel.NewStore(cfg.StoreRoot+"/kernel", BlockchainVersion, act, tran, cfg.ForceRecover); err != nil {
-		if cfg.ForceRecover || err != badger.ErrTruncateNeeded {
+	if s, err := kernel.NewStore(cfg.StoreRoot+"/kernel", BlockchainVersion, act, tran, cfg.ForceRecover && !reindexMode); err != nil {
+		if reindexMode || cfg.ForceRecover || err != badger.ErrTruncateNeeded {
 			panic(err)
 		} else {
 			fmt.Println(err)
@@ -104,6 +108,13 @@ func main() {
 	}
 	cm.Add("kernel.Store", ks)
 
+	if reindexMode {
+		if err := reindex(ks, tran, GenesisContextData, "./explorer_data", cfg.ExplorerIndexWorkers); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	rd := &reward.TestNetRewarder{}
 	kn, err := kernel.NewKernel(&kernel.Config{
 		ChainCoord:              GenCoord,
@@ -117,10 +128,12 @@ func main() {
 	cm.RemoveAll()
 	cm.Add("kernel.Kernel", kn)
 
-	be, err := blockexplorer.NewBlockExplorer("./explorer_data", kn, "./webfiles")
+	be, err := blockexplorer.NewBlockExplorerWithGenesis("./explorer_data", kn, "./webfiles", GenesisContextData)
 	if err != nil {
 		panic(err)
 	}
+	be.SetIndexWorkers(cfg.ExplorerIndexWorkers)
+	kn.AddEventHandler(be)
 	go be.StartExplorer(cfg.ExplorerPort)
 
 	ndcfg := &node.Config{
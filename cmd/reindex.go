@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	blockexplorer "github.com/fletaio/block_explorer"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/framework/chain"
+)
+
+// storeSource reads the blocks from the kernel store without the kernel
+type storeSource struct {
+	provider   chain.Provider
+	transactor *data.Transactor
+}
+
+func (s *storeSource) Provider() chain.Provider {
+	return s.provider
+}
+
+func (s *storeSource) Block(height uint32) (*block.Block, error) {
+	cd, err := s.provider.Data(height)
+	if err != nil {
+		return nil, err
+	}
+	b := &block.Block{
+		Header: cd.Header.(*block.Header),
+		Body:   cd.Body.(*block.Body),
+	}
+	return b, nil
+}
+
+func (s *storeSource) Transactor() *data.Transactor {
+	return s.transactor
+}
+
+// reindex rebuilds the explorer db from the blocks of the kernel store
+// the kernel is not created so the kernel store is only read while the reindex is running
+func reindex(ks *kernel.Store, tran *data.Transactor, GenesisContextData *data.ContextData, dbPath string, workers int) error {
+	source := &storeSource{
+		provider:   ks,
+		transactor: tran,
+	}
+	fmt.Println("Reindex", dbPath, "to the height", source.Provider().Height())
+	if err := blockexplorer.Reindex(dbPath, source, GenesisContextData, workers, func(p *blockexplorer.ReindexProgress) {
+		fmt.Printf("Reindex %d/%d (%.1f%%) %d txs, %.1f blocks/s, %.1f txs/s, %s elapsed\n",
+			p.Height, p.Target, float64(p.Height)*100/float64(p.Target), p.Transactions,
+			p.BlocksPerSec, p.TxsPerSec, p.Elapsed.Truncate(1e9))
+	}); err != nil {
+		return err
+	}
+	fmt.Println("Reindex is done")
+	return nil
+}
@@ -0,0 +1,354 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/transaction"
+)
+
+// solidity transaction names
+const (
+	contractCreateTxName = "solidity.CreateContract"
+	contractCallTxName   = "solidity.CallContract"
+)
+
+// contractRecord is the deployment of a contract and the number of the calls to it
+type contractRecord struct {
+	Creator common.Address
+	TxHash  hash.Hash256
+	Height  uint32
+	Index   uint32
+	Calls   uint32
+}
+
+// WriteTo is a serialization function
+func (cr *contractRecord) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := w.Write(cr.Creator[:]); err != nil {
+		return wrote, err
+	} else {
+		wrote += int64(n)
+	}
+	if n, err := w.Write(cr.TxHash[:]); err != nil {
+		return wrote, err
+	} else {
+		wrote += int64(n)
+	}
+	if n, err := util.WriteUint32(w, cr.Height); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, cr.Index); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, cr.Calls); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (cr *contractRecord) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := io.ReadFull(r, cr.Creator[:]); err != nil {
+		return read, err
+	} else {
+		read += int64(n)
+	}
+	if n, err := io.ReadFull(r, cr.TxHash[:]); err != nil {
+		return read, err
+	} else {
+		read += int64(n)
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		cr.Height = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		cr.Index = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		cr.Calls = v
+	}
+	return read, nil
+}
+
+func readContractRecord(value []byte) (*contractRecord, error) {
+	cr := &contractRecord{}
+	if _, err := cr.ReadFrom(bytes.NewReader(value)); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func setContractRecord(it *indexTxn, addr common.Address, cr *contractRecord) error {
+	buf := &bytes.Buffer{}
+	if _, err := cr.WriteTo(buf); err != nil {
+		return err
+	}
+	return it.Set(contractKey(addr), buf.Bytes())
+}
+
+// txSigner returns the address which sent the transaction
+func txSigner(tx transaction.Transaction) common.Address {
+	if s, ok := tx.(interface {
+		From() common.Address
+	}); ok {
+		return s.From()
+	}
+	if addrs := txFieldAddresses(tx); len(addrs) > 0 {
+		return addrs[0]
+	}
+	return common.Address{}
+}
+
+func txFieldAddresses(tx transaction.Transaction) []common.Address {
+	addrs := []common.Address{}
+	collectAddresses(reflect.ValueOf(tx), map[common.Address]bool{}, &addrs)
+	return addrs
+}
+
+// updateContracts records the contracts deployed by the block and the calls to the known contracts
+func (e *BlockExplorer) updateContracts(it *indexTxn, b *block.Block, height uint32) error {
+	for i, tx := range b.Body.Transactions {
+		name, err := e.source.Transactor().NameByType(tx.Type())
+		if err != nil {
+			continue
+		}
+		switch name {
+		case contractCreateTxName:
+			addr := common.NewAddress(common.NewCoordinate(height, uint16(i)), 0)
+			cr := &contractRecord{
+				Creator: txSigner(tx),
+				TxHash:  tx.Hash(),
+				Height:  height,
+				Index:   uint32(i),
+			}
+			if err := setContractRecord(it, addr, cr); err != nil {
+				return err
+			}
+			if err := it.Set(contractHeightKey(height, uint32(i)), addr[:]); err != nil {
+				return err
+			}
+			if err := it.Increase(contractCountKey); err != nil {
+				return err
+			}
+		case contractCallTxName:
+			signer := txSigner(tx)
+			for _, addr := range txFieldAddresses(tx) {
+				if addr == signer {
+					continue
+				}
+				value, err := it.Get(contractKey(addr))
+				if err != nil {
+					return err
+				}
+				if value == nil {
+					continue
+				}
+				cr, err := readContractRecord(value)
+				if err != nil {
+					return err
+				}
+				cr.Calls++
+				if err := setContractRecord(it, addr, cr); err != nil {
+					return err
+				}
+				if err := it.Set(contractCallKey(addr, height, uint32(i)), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// contractRecordOf returns the deployment of the contract or nil when the address is not a known contract
+func (e *BlockExplorer) contractRecordOf(addr common.Address) (cr *contractRecord) {
+	e.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(contractKey(addr))
+		if err != nil {
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		cr, err = readContractRecord(value)
+		return err
+	})
+	return
+}
+
+// ContractCount returns the number of the deployed contracts
+func (e *BlockExplorer) ContractCount() (count uint32) {
+	e.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(contractCountKey)
+		if err != nil {
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		count = util.BytesToUint32(value)
+		return nil
+	})
+	return
+}
+
+type contractInfos struct {
+	Address string `json:"Address"`
+	Creator string `json:"Creator"`
+	TxHash  string `json:"TxHash"`
+	Height  uint32 `json:"Height"`
+	Calls   uint32 `json:"Calls"`
+}
+
+type contractInfosCase struct {
+	ITotalRecords        int             `json:"iTotalRecords"`
+	ITotalDisplayRecords int             `json:"iTotalDisplayRecords"`
+	SEcho                int             `json:"sEcho"`
+	SColumns             string          `json:"sColumns"`
+	AaData               []contractInfos `json:"aaData"`
+}
+
+func newContractInfos(addr common.Address, cr *contractRecord) contractInfos {
+	return contractInfos{
+		Address: addr.String(),
+		Creator: cr.Creator.String(),
+		TxHash:  cr.TxHash.String(),
+		Height:  cr.Height,
+		Calls:   cr.Calls,
+	}
+}
+
+// contractList returns the contracts from the newest deployment skipping start
+func (e *BlockExplorer) contractList(start int, length int) []contractInfos {
+	list := []contractInfos{}
+	e.db.View(func(txn *badger.Txn) error {
+		prefix := []byte{contractHeightPrefix}
+
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		skipped := 0
+		for it.Seek(contractHeightKey(^uint32(0), ^uint32(0))); it.ValidForPrefix(prefix) && len(list) < length; it.Next() {
+			if skipped < start {
+				skipped++
+				continue
+			}
+			value, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			var addr common.Address
+			copy(addr[:], value)
+
+			item, err := txn.Get(contractKey(addr))
+			if err != nil {
+				continue
+			}
+			rv, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			cr, err := readContractRecord(rv)
+			if err != nil {
+				continue
+			}
+			list = append(list, newContractInfos(addr, cr))
+		}
+		return nil
+	})
+	return list
+}
+
+func (e *BlockExplorer) contracts(startStr string) (result contractInfosCase, err error) {
+	start, err := parseStart(startStr)
+	if err != nil {
+		return result, err
+	}
+
+	count := int(e.ContractCount())
+	result.ITotalRecords = count
+	result.ITotalDisplayRecords = count
+
+	result.AaData = e.contractList(start, 10)
+	return
+}
+
+func (e *BlockExplorer) contractCallList(addr common.Address, start int, length int) []txInfos {
+	positions := []txPosition{}
+
+	e.db.View(func(txn *badger.Txn) error {
+		prefix := contractCallPrefixKey(addr)
+
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Reverse = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		skipped := 0
+		for it.Seek(contractCallKey(addr, ^uint32(0), ^uint32(0))); it.ValidForPrefix(prefix) && len(positions) < length; it.Next() {
+			if skipped < start {
+				skipped++
+				continue
+			}
+			key := it.Item().Key()[len(prefix):]
+			positions = append(positions, txPosition{
+				height: keyToUint32(key[0:4]),
+				index:  keyToUint32(key[4:8]),
+			})
+		}
+		return nil
+	})
+
+	return e.txInfosByPosition(positions)
+}
+
+func (e *BlockExplorer) contractCalls(addrStr string, startStr string) (result txInfosCase, err error) {
+	result.AaData = []txInfos{}
+
+	addr, err := parseAddressParam(addrStr)
+	if err != nil {
+		return result, err
+	}
+	start, err := parseStart(startStr)
+	if err != nil {
+		return result, err
+	}
+
+	cr := e.contractRecordOf(addr)
+	if cr == nil {
+		return result, ErrNotContract
+	}
+	result.ITotalRecords = int(cr.Calls)
+	result.ITotalDisplayRecords = int(cr.Calls)
+
+	result.AaData = e.contractCallList(addr, start, 10)
+	return
+}
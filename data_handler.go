@@ -0,0 +1,222 @@
+package blockexplorer
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo"
+)
+
+// DataHandler is a handler of the /data/:order route registered by the order name
+type DataHandler struct {
+	Order   string
+	Method  string // every method is allowed when it is empty
+	Handler func(c echo.Context) (interface{}, error)
+	Doc     *APIDoc // published in the OpenAPI document when it is given
+}
+
+// DataHandlerPack interface of handler
+type DataHandlerPack interface {
+	DataHandler(c echo.Context) (interface{}, error)
+}
+
+// AddDataHandler adds the pack which is tried in the added order for the orders which are not registered
+//
+// Deprecated: the pack answers every order it does not return an error for, use RegisterDataHandler or AddDataHandlerPack
+func (e *BlockExplorer) AddDataHandler(d DataHandlerPack) {
+	e.dataHandlersLock.Lock()
+	defer e.dataHandlersLock.Unlock()
+
+	e.dataHandlerPacks = append(e.dataHandlerPacks, d)
+}
+
+// RegisterDataHandler registers the handler by the order name, the order can not be registered twice
+func (e *BlockExplorer) RegisterDataHandler(h *DataHandler) error {
+	if h.Order == "" || h.Handler == nil {
+		return ErrNotEnoughParameter
+	}
+
+	e.dataHandlersLock.Lock()
+	defer e.dataHandlersLock.Unlock()
+
+	if e.dataHandlers == nil {
+		e.dataHandlers = map[string]*DataHandler{}
+	}
+	if _, has := e.dataHandlers[h.Order]; has {
+		return ErrDuplicateDataOrder
+	}
+	e.dataHandlers[h.Order] = h
+	return nil
+}
+
+// AddDataHandlerPack registers the pack for the orders which it handles
+// the pack is called with the order in the route parameter as before
+func (e *BlockExplorer) AddDataHandlerPack(d DataHandlerPack, orders ...string) error {
+	if len(orders) == 0 {
+		return ErrNotEnoughParameter
+	}
+	for _, order := range orders {
+		if err := e.RegisterDataHandler(&DataHandler{
+			Order:   order,
+			Handler: d.DataHandler,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dataHandlerList returns the registered handlers sorted by the order name
+func (e *BlockExplorer) dataHandlerList() []*DataHandler {
+	e.dataHandlersLock.RLock()
+	defer e.dataHandlersLock.RUnlock()
+
+	list := make([]*DataHandler, 0, len(e.dataHandlers))
+	for _, h := range e.dataHandlers {
+		list = append(list, h)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Order < list[j].Order
+	})
+	return list
+}
+
+func (e *BlockExplorer) dataHandler(c echo.Context) error {
+	e.dataHandlersLock.RLock()
+	h, has := e.dataHandlers[c.Param("order")]
+	packs := e.dataHandlerPacks
+	e.dataHandlersLock.RUnlock()
+	if !has {
+		// the packs added by the deprecated AddDataHandler answer the orders which they know
+		for _, d := range packs {
+			if result, err := d.DataHandler(c); err == nil {
+				return c.JSON(http.StatusOK, result)
+			}
+		}
+		return ErrUnknownDataOrder
+	}
+	if h.Method != "" && h.Method != c.Request().Method {
+		return echo.ErrMethodNotAllowed
+	}
+
+	result, err := h.Handler(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// initDataHandlers registers the built-in data handlers
+func (e *BlockExplorer) initDataHandlers() {
+	for _, h := range []*DataHandler{
+		{
+			Order: "transactions.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.transactions(c.QueryParam("range"), c.QueryParam("res"), c.QueryParam("metric")), nil
+			},
+			Doc: &APIDoc{Summary: "throughput chart of the range by the resolution", Parameters: queryParams("range", "res", "metric"), Response: []*countInfo{}},
+		},
+		{
+			Order: "currentChainInfo.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.chainInfo(), nil
+			},
+			Doc: &APIDoc{Summary: "chain counters and tps", Response: chainInfoData{}},
+		},
+		{
+			Order: "lastestBlocks.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.lastestBlocks(), nil
+			},
+			Doc: &APIDoc{Summary: "recent blocks", Response: blockInfosCase{}},
+		},
+		{
+			Order: "lastestTransactions.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.lastestTransactions(), nil
+			},
+			Doc: &APIDoc{Summary: "recent transactions", Response: []txInfos{}},
+		},
+		{
+			Order: "paginationBlocks.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.paginationBlocks(c.QueryParam("start"))
+			},
+			Doc: &APIDoc{Summary: "block page", Parameters: queryParams("start"), Response: blockInfosCase{}},
+		},
+		{
+			Order: "paginationTxs.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.paginationTxs(c.QueryParam("start"))
+			},
+			Doc: &APIDoc{Summary: "transaction page", Parameters: queryParams("start"), Response: txInfosCase{}},
+		},
+		{
+			Order: "typesPerBlock.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.typesPerBlock(c.QueryParam("from"), c.QueryParam("to"))
+			},
+			Doc: &APIDoc{Summary: "transaction counts by the type per block", Parameters: queryParams("from", "to"), Response: []typePerBlock{}},
+		},
+		{
+			Order: "formulators.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.formulators(c.QueryParam("start"), c.QueryParam("sort"), c.QueryParam("order"))
+			},
+			Doc: &APIDoc{Summary: "formulator leaderboard page", Parameters: queryParams("start", "sort", "order"), Response: formulatorInfosCase{}},
+		},
+		{
+			Order: "chainInfoTable.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.chainInfoTable(), nil
+			},
+			Doc: &APIDoc{Summary: "block and transaction counts per chain", Response: chainInfosCase{}},
+		},
+		{
+			Order: "status.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.status(), nil
+			},
+			Doc: &APIDoc{Summary: "indexer status", Response: indexerStatus{}},
+		},
+		{
+			Order: "account.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.accountData(c.QueryParam("addr"))
+			},
+			Doc: &APIDoc{Summary: "current account state", Parameters: queryParams("addr"), Response: &accountInfo{}},
+		},
+		{
+			Order: "contracts.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.contracts(c.QueryParam("start"))
+			},
+			Doc: &APIDoc{Summary: "deployed contract page", Parameters: queryParams("start"), Response: contractInfosCase{}},
+		},
+		{
+			Order: "contractCalls.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.contractCalls(c.QueryParam("addr"), c.QueryParam("start"))
+			},
+			Doc: &APIDoc{Summary: "call page of the contract", Parameters: queryParams("addr", "start"), Response: txInfosCase{}},
+		},
+		{
+			Order: "search.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.search(c.QueryParam("q")), nil
+			},
+			Doc: &APIDoc{Summary: "search matches and hash suggestions", Parameters: queryParams("q"), Response: &searchResult{}},
+		},
+		{
+			Order: "addressTxs.data",
+			Handler: func(c echo.Context) (interface{}, error) {
+				return e.addressTxs(c.QueryParam("addr"), c.QueryParam("start"))
+			},
+			Doc: &APIDoc{Summary: "transaction page of the address", Parameters: queryParams("addr", "start"), Response: txInfosCase{}},
+		},
+	} {
+		if err := e.RegisterDataHandler(h); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package blockexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/labstack/echo"
+)
+
+type testDataPack struct{}
+
+func (p *testDataPack) DataHandler(c echo.Context) (interface{}, error) {
+	return c.Param("order"), nil
+}
+
+// testLegacyPack answers only its own order as the packs of the old AddDataHandler
+type testLegacyPack struct{}
+
+func (p *testLegacyPack) DataHandler(c echo.Context) (interface{}, error) {
+	if c.Param("order") != "legacy.data" {
+		return nil, ErrUnknownDataOrder
+	}
+	return "legacy", nil
+}
+
+func TestDataHandlerRegistry(t *testing.T) {
+	e := &BlockExplorer{}
+	e.initDataHandlers()
+
+	if err := e.RegisterDataHandler(&DataHandler{
+		Order: "status.data",
+		Handler: func(c echo.Context) (interface{}, error) {
+			return nil, nil
+		},
+	}); err != ErrDuplicateDataOrder {
+		t.Fatalf("the built-in order is registered again: %v", err)
+	}
+	if err := e.AddDataHandlerPack(&testDataPack{}, "pack.data"); err != nil {
+		t.Fatal(err)
+	}
+	e.AddDataHandler(&testLegacyPack{})
+
+	ec := echo.New()
+	for order, status := range map[string]int{
+		"pack.data":    http.StatusOK,
+		"legacy.data":  http.StatusOK,
+		"unknown.data": http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		c := ec.NewContext(httptest.NewRequest("GET", "/data/"+order, nil), rec)
+		c.SetParamNames("order")
+		c.SetParamValues(order)
+		if err := e.dataHandler(c); err != nil {
+			if httpStatusOf(err) != status {
+				t.Errorf("%s: %v", order, err)
+			}
+		} else if rec.Code != status {
+			t.Errorf("%s: status %d", order, rec.Code)
+		}
+	}
+}
+
+func TestDataRouteErrors(t *testing.T) {
+	db, closeDB := openTestDB(t)
+	defer closeDB()
+
+	e := &BlockExplorer{db: db}
+	e.initDataHandlers()
+	ec := echo.New()
+	ec.HTTPErrorHandler = e.httpErrorHandler
+	ec.Any("/data/:order", e.dataHandler)
+
+	addr := common.NewAddress(common.NewCoordinate(1, 0), 0).String()
+	for _, tc := range []struct {
+		path   string
+		status int
+	}{
+		{"/data/account.data", http.StatusBadRequest},
+		{"/data/account.data?addr=bad", http.StatusBadRequest},
+		{"/data/addressTxs.data?addr=bad", http.StatusBadRequest},
+		{"/data/addressTxs.data?addr=" + addr + "&start=x", http.StatusBadRequest},
+		{"/data/contractCalls.data?addr=bad", http.StatusBadRequest},
+		{"/data/contractCalls.data?addr=" + addr, http.StatusNotFound},
+		{"/data/paginationBlocks.data", http.StatusBadRequest},
+		{"/data/paginationTxs.data?start=-1", http.StatusBadRequest},
+		{"/data/contracts.data?start=x", http.StatusBadRequest},
+		{"/data/typesPerBlock.data?from=x", http.StatusBadRequest},
+		{"/data/unknown.data", http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		ec.ServeHTTP(rec, httptest.NewRequest("GET", tc.path, nil))
+		if rec.Code != tc.status {
+			t.Errorf("%s: status %d, expected %d", tc.path, rec.Code, tc.status)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package blockexplorer
+
+import (
+	"encoding/binary"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+)
+
+// key prefixes of the explorer db, every index type has its own namespace
+const (
+	metaPrefix            byte = 0x01 // name -> meta value (schema version, chain info, cursor, ...)
+	blockHashPrefix       byte = 0x10 // block hash -> height
+	txHashPrefix          byte = 0x11 // tx hash -> height + index
+	heightHashPrefix      byte = 0x12 // height -> indexed block hash
+	blockUndoPrefix       byte = 0x13 // height -> undo of the block
+	blockTypesPrefix      byte = 0x14 // height -> tx counts by the type name
+	blockFormulatorPrefix byte = 0x15 // height -> formulator address
+	txSequencePrefix      byte = 0x20 // sequence -> height + index
+	formulatorPrefix      byte = 0x30 // formulator address -> formulator record
+	addressTxPrefix       byte = 0x40 // address + height + index -> empty
+	addressTxCountPrefix  byte = 0x41 // address -> tx count
+	chainPrefix           byte = 0x50 // chain coordinate -> chain record
+	chainFormulatorPrefix byte = 0x51 // chain coordinate + formulator address -> empty
+	rollupPrefix          byte = 0x60 // resolution + bucket -> rollup record
+	utxoPrefix            byte = 0x70 // height + index + n -> utxo record
+	contractPrefix        byte = 0x80 // contract address -> contract record
+	contractCallPrefix    byte = 0x81 // contract address + height + index -> empty
+	contractHeightPrefix  byte = 0x82 // height + index -> contract address
+	namePrefix            byte = 0x90 // account name -> address
+	addressNamePrefix     byte = 0x91 // address -> account name
+)
+
+// keyUint32 encodes the number in big endian so the keys are sorted by the number
+func keyUint32(v uint32) []byte {
+	bs := make([]byte, 4)
+	binary.BigEndian.PutUint32(bs, v)
+	return bs
+}
+
+func keyToUint32(bs []byte) uint32 {
+	return binary.BigEndian.Uint32(bs)
+}
+
+func keyUint64(v uint64) []byte {
+	bs := make([]byte, 8)
+	binary.BigEndian.PutUint64(bs, v)
+	return bs
+}
+
+func keyToUint64(bs []byte) uint64 {
+	return binary.BigEndian.Uint64(bs)
+}
+
+func keyUint16(v uint16) []byte {
+	bs := make([]byte, 2)
+	binary.BigEndian.PutUint16(bs, v)
+	return bs
+}
+
+func keyToUint16(bs []byte) uint16 {
+	return binary.BigEndian.Uint16(bs)
+}
+
+func metaKey(name string) []byte {
+	return append([]byte{metaPrefix}, name...)
+}
+
+// meta keys
+var (
+	schemaVersionKey  = metaKey("schemaVersion")
+	blockChainInfoKey = metaKey("blockChainInfo")
+	maximumTpsKey     = metaKey("MaximumTps") // converted into the tps peaks at the schema version 3
+	tpsPeaksKey       = metaKey("tpsPeaks")
+	indexCursorKey    = metaKey("indexCursor")
+	txCountKey        = metaKey("txCount")
+	contractCountKey  = metaKey("contractCount")
+	genesisNamesKey   = metaKey("genesisNames")
+)
+
+func blockHashKey(h hash.Hash256) []byte {
+	return append([]byte{blockHashPrefix}, h[:]...)
+}
+
+func txHashKey(h hash.Hash256) []byte {
+	return append([]byte{txHashPrefix}, h[:]...)
+}
+
+func heightHashKey(height uint32) []byte {
+	return append([]byte{heightHashPrefix}, keyUint32(height)...)
+}
+
+func blockUndoKey(height uint32) []byte {
+	return append([]byte{blockUndoPrefix}, keyUint32(height)...)
+}
+
+func blockTypesKey(height uint32) []byte {
+	return append([]byte{blockTypesPrefix}, keyUint32(height)...)
+}
+
+func blockFormulatorKey(height uint32) []byte {
+	return append([]byte{blockFormulatorPrefix}, keyUint32(height)...)
+}
+
+func txSequenceKey(seq uint64) []byte {
+	return append([]byte{txSequencePrefix}, util.Uint64ToBytes(seq)...)
+}
+
+func formulatorKey(addr common.Address) []byte {
+	return append([]byte{formulatorPrefix}, addr[:]...)
+}
+
+func addressTxPrefixKey(addr common.Address) []byte {
+	return append([]byte{addressTxPrefix}, addr[:]...)
+}
+
+func addressTxKey(addr common.Address, height uint32, index uint32) []byte {
+	key := make([]byte, 0, 1+len(addr)+8)
+	key = append(key, addressTxPrefix)
+	key = append(key, addr[:]...)
+	key = append(key, keyUint32(height)...)
+	key = append(key, keyUint32(index)...)
+	return key
+}
+
+func addressTxCountKey(addr common.Address) []byte {
+	return append([]byte{addressTxCountPrefix}, addr[:]...)
+}
+
+func chainKey(coord *common.Coordinate) []byte {
+	key := make([]byte, 0, 7)
+	key = append(key, chainPrefix)
+	key = append(key, keyUint32(coord.Height)...)
+	key = append(key, keyUint16(coord.Index)...)
+	return key
+}
+
+func chainFormulatorKey(coord *common.Coordinate, addr common.Address) []byte {
+	key := make([]byte, 0, 7+len(addr))
+	key = append(key, chainFormulatorPrefix)
+	key = append(key, keyUint32(coord.Height)...)
+	key = append(key, keyUint16(coord.Index)...)
+	key = append(key, addr[:]...)
+	return key
+}
+
+func rollupKey(res byte, bucket uint64) []byte {
+	return append([]byte{rollupPrefix, res}, keyUint64(bucket)...)
+}
+
+func utxoKey(height uint32, index uint16, n uint16) []byte {
+	key := make([]byte, 0, 9)
+	key = append(key, utxoPrefix)
+	key = append(key, keyUint32(height)...)
+	key = append(key, keyUint16(index)...)
+	key = append(key, keyUint16(n)...)
+	return key
+}
+
+func contractKey(addr common.Address) []byte {
+	return append([]byte{contractPrefix}, addr[:]...)
+}
+
+func contractCallPrefixKey(addr common.Address) []byte {
+	return append([]byte{contractCallPrefix}, addr[:]...)
+}
+
+func contractCallKey(addr common.Address, height uint32, index uint32) []byte {
+	key := make([]byte, 0, 1+len(addr)+8)
+	key = append(key, contractCallPrefix)
+	key = append(key, addr[:]...)
+	key = append(key, keyUint32(height)...)
+	key = append(key, keyUint32(index)...)
+	return key
+}
+
+func contractHeightKey(height uint32, index uint32) []byte {
+	key := make([]byte, 0, 9)
+	key = append(key, contractHeightPrefix)
+	key = append(key, keyUint32(height)...)
+	key = append(key, keyUint32(index)...)
+	return key
+}
+
+func nameKey(name string) []byte {
+	return append([]byte{namePrefix}, name...)
+}
+
+func addressNameKey(addr common.Address) []byte {
+	return append([]byte{addressNamePrefix}, addr[:]...)
+}
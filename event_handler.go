@@ -0,0 +1,49 @@
+package blockexplorer
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/message_def"
+	"github.com/fletaio/core/transaction"
+)
+
+// BlockExplorer is registered to the kernel by kernel.AddEventHandler
+// the handlers are called by the kernel so they never touch the kernel and never block
+var _ kernel.EventHandler = &BlockExplorer{}
+
+// OnCreateContext called when a context creation (error prevent using context)
+func (e *BlockExplorer) OnCreateContext(kn *kernel.Kernel, ctx *data.Context) error {
+	return nil
+}
+
+// OnProcessBlock called when processing a block to the chain (error prevent processing block)
+func (e *BlockExplorer) OnProcessBlock(kn *kernel.Kernel, b *block.Block, s *block.ObserverSigned, ctx *data.Context) error {
+	return nil
+}
+
+// AfterProcessBlock wakes the indexer to index the connected block
+func (e *BlockExplorer) AfterProcessBlock(kn *kernel.Kernel, b *block.Block, s *block.ObserverSigned, ctx *data.Context) {
+	select {
+	case e.blockEvent <- struct{}{}:
+	default:
+	}
+}
+
+// OnPushTransaction called when pushing a transaction to the transaction pool (error prevent push transaction)
+func (e *BlockExplorer) OnPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) error {
+	return nil
+}
+
+// AfterPushTransaction called when pushed a transaction to the transaction pool
+func (e *BlockExplorer) AfterPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) {
+}
+
+// DoTransactionBroadcast called when a transaction need to be broadcast
+func (e *BlockExplorer) DoTransactionBroadcast(kn *kernel.Kernel, msg *message_def.TransactionMessage) {
+}
+
+// DebugLog TEMP
+func (e *BlockExplorer) DebugLog(kn *kernel.Kernel, args ...interface{}) {
+}
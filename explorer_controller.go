@@ -9,6 +9,7 @@ import (
 	"github.com/fletaio/common/hash"
 
 	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
 	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 )
@@ -40,19 +41,116 @@ func (e *ExplorerController) Transactions(r *http.Request) (map[string]string, e
 		"txLength": strconv.Itoa(e.block.LastestTransactionLen()),
 	}, nil
 }
+func (e *ExplorerController) Formulators(r *http.Request) (map[string]string, error) {
+	param := r.URL.Query()
+	sortKey := param.Get("sort")
+	if _, has := formulatorSortFuncs[sortKey]; !has {
+		sortKey = "blocks"
+	}
+	order := param.Get("order")
+	if order != "asc" {
+		order = "desc"
+	}
+
+	data, _ := e.block.formulators("0", sortKey, order)
+	j, _ := json.Marshal(data.AaData)
+	return map[string]string{
+		"formulatorsData":  string(j),
+		"formulatorLength": strconv.Itoa(data.ITotalRecords),
+		"sort":             sortKey,
+		"order":            order,
+	}, nil
+}
+func (e *ExplorerController) Address(r *http.Request) (map[string]string, error) {
+	param := r.URL.Query()
+	addrStr := param.Get("addr")
+	if addrStr == "" {
+		return nil, ErrNotEnoughParameter
+	}
+	addr, err := common.ParseAddress(addrStr)
+	if err != nil {
+		return nil, ErrInvalidAddressFormat
+	}
+
+	data := e.block.addressTxList(addr, 0, 10)
+	j, _ := json.Marshal(data)
+	return map[string]string{
+		"addr":     addr.String(),
+		"txsData":  string(j),
+		"txLength": strconv.Itoa(int(e.block.AddressTxCount(addr))),
+	}, nil
+}
+func (e *ExplorerController) Account(r *http.Request) (map[string]string, error) {
+	param := r.URL.Query()
+	addrStr := param.Get("addr")
+	if addrStr == "" {
+		return nil, ErrNotEnoughParameter
+	}
+	addr, err := common.ParseAddress(addrStr)
+	if err != nil {
+		return nil, ErrInvalidAddressFormat
+	}
+
+	info, err := e.block.accountInfoOf(addr)
+	if err != nil {
+		return map[string]string{
+			"addr": addr.String(),
+		}, err
+	}
+	j, _ := json.Marshal(info)
+	return map[string]string{
+		"addr":        addr.String(),
+		"accountData": string(j),
+	}, nil
+}
+func (e *ExplorerController) Contract(r *http.Request) (map[string]string, error) {
+	param := r.URL.Query()
+	addrStr := param.Get("addr")
+	if addrStr == "" {
+		data, _ := e.block.contracts("0")
+		j, _ := json.Marshal(data.AaData)
+		return map[string]string{
+			"contractsData":  string(j),
+			"contractLength": strconv.Itoa(data.ITotalRecords),
+		}, nil
+	}
+	addr, err := common.ParseAddress(addrStr)
+	if err != nil {
+		return nil, ErrInvalidAddressFormat
+	}
+
+	cr := e.block.contractRecordOf(addr)
+	if cr == nil {
+		return map[string]string{
+			"addr": addr.String(),
+		}, ErrNotContract
+	}
+	info, _ := json.Marshal(newContractInfos(addr, cr))
+	calls, _ := json.Marshal(e.block.contractCallList(addr, 0, 10))
+	return map[string]string{
+		"addr":         addr.String(),
+		"contractData": string(info),
+		"callsData":    string(calls),
+		"callLength":   strconv.Itoa(int(cr.Calls)),
+	}, nil
+}
 func (e *ExplorerController) BlockDetail(r *http.Request) (map[string]string, error) {
 	param := r.URL.Query()
 	// hash := param.Get("hash")
 	heightStr := param.Get("height")
 	var height uint32
 	if heightStr == "" {
-		hash := param.Get("hash")
-		if hash == "" {
+		hashStr := param.Get("hash")
+		if hashStr == "" {
 			return nil, ErrNotEnoughParameter
 		}
+		h, err := hash.ParseHex(hashStr)
+		if err != nil {
+			return nil, ErrNotBlockHash
+		}
 
 		if err := e.db.View(func(txn *badger.Txn) error {
-			item, err := txn.Get([]byte(hash))
+			item, err := txn.Get(blockHashKey(h))
 			if err != nil {
 				return err
 			}
@@ -76,6 +174,9 @@ func (e *ExplorerController) BlockDetail(r *http.Request) (map[string]string, er
 		}
 		height = uint32(heightInt)
 	}
+	if height > e.block.Kernel.Provider().Height() {
+		return nil, ErrNotExistBlock
+	}
 
 	m, err := e.block.blockDetailMap(height)
 	if err != nil {
@@ -90,12 +191,15 @@ func (e *ExplorerController) TransactionDetail(r *http.Request) (map[string]stri
 	hashStr := param.Get("hash")
 	h, err := hash.ParseHex(hashStr)
 	if err != nil {
-		return nil, err
+		return nil, ErrNotTransactionHash
 	}
 	var v []byte
 	if err := e.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(h[:])
+		item, err := txn.Get(txHashKey(h))
 		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return ErrNotTransactionHash
+			}
 			return err
 		}
 		v, err = item.ValueCopy(nil)
@@ -127,6 +231,9 @@ func (e *BlockExplorer) txDetailMap(tran *data.Transactor, height uint32, txInde
 	if err != nil {
 		return nil, err
 	}
+	if int(txIndex) >= len(b.Body.Transactions) {
+		return nil, ErrNotTransactionHash
+	}
 	t := b.Body.Transactions[int(txIndex)]
 
 	cd, err := e.Kernel.Provider().Data(height)
@@ -147,6 +254,9 @@ func (e *BlockExplorer) txDetailMap(tran *data.Transactor, height uint32, txInde
 	m["Tx Hash"] = t.Hash().String()
 	tm = time.Unix(int64(t.Timestamp()/uint64(time.Second)), 0)
 	m["Tx TimeStamp"] = tm.Format("2006-01-02 15:04:05")
+	if names := e.namesOf(e.txAddresses(t, height, txIndex)); len(names) > 0 {
+		m["Names"] = names
+	}
 
 	bs, err := json.Marshal(&m)
 	if err != nil {
@@ -159,7 +269,16 @@ func (e *BlockExplorer) txDetailMap(tran *data.Transactor, height uint32, txInde
 	}
 	bs = append(bs[:len(bs)-1], byte(','))
 	bs = append(bs, txbs[1:]...)
-	return map[string]string{"TxInfo": string(bs)}, nil
+	result := map[string]string{"TxInfo": string(bs)}
+
+	if d := e.utxoDetailOf(t, height, uint16(txIndex)); d != nil {
+		ubs, err := json.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		result["UTXOInfo"] = string(ubs)
+	}
+	return result, nil
 }
 
 func (e *BlockExplorer) blockDetailMap(height uint32) (map[string]string, error) {
@@ -182,6 +301,9 @@ func (e *BlockExplorer) blockDetailMap(height uint32) (map[string]string, error)
 	m["HashLevelRoot"] = b.Header.LevelRootHash.String()
 	m["Timestamp"] = tm.Format("2006-01-02 15:04:05")
 	m["FormulationAddress"] = b.Header.Formulator.String()
+	if name := e.nameOf(b.Header.Formulator); name != "" {
+		m["FormulatorName"] = name
+	}
 	m["TimeoutCount"] = strconv.Itoa(int(b.Header.TimeoutCount))
 	m["Transaction Count"] = strconv.Itoa(len(b.Body.Transactions))
 
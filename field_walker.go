@@ -0,0 +1,44 @@
+package blockexplorer
+
+import "reflect"
+
+// indirect returns the value behind the pointers and the interfaces, it returns false when one of them is nil
+func indirect(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid()
+}
+
+// walkFields calls the visit with the exported fields of the struct behind the value until it returns false
+// the transactions and the accounts are read by their fields because every type of them defines its own struct,
+// so the fields of the embedded structs are walked as the fields of the struct which embeds them
+// it returns false when the walk is stopped by the visit
+func walkFields(v reflect.Value, visit func(f reflect.StructField, fv reflect.Value) bool) bool {
+	v, ok := indirect(v)
+	if !ok || v.Kind() != reflect.Struct {
+		return true
+	}
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if f.Anonymous {
+			if ev, ok := indirect(v.Field(i)); ok && ev.Kind() == reflect.Struct {
+				if !walkFields(ev, visit) {
+					return false
+				}
+				continue
+			}
+		}
+		if !visit(f, v.Field(i)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,302 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+)
+
+// recentShareWindow is the number of recent blocks used to calculate the share of the formulators
+const recentShareWindow = 1000
+
+// formulatorRecord is the block production record of a formulator
+type formulatorRecord struct {
+	Blocks        uint32
+	FirstHeight   uint32
+	LastHeight    uint32
+	TimeoutBlocks uint32
+}
+
+// WriteTo is a serialization function
+func (fr *formulatorRecord) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	for _, v := range []uint32{fr.Blocks, fr.FirstHeight, fr.LastHeight, fr.TimeoutBlocks} {
+		if n, err := util.WriteUint32(w, v); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (fr *formulatorRecord) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	for _, v := range []*uint32{&fr.Blocks, &fr.FirstHeight, &fr.LastHeight, &fr.TimeoutBlocks} {
+		if value, n, err := util.ReadUint32(r); err != nil {
+			return read, err
+		} else {
+			read += n
+			*v = value
+		}
+	}
+	return read, nil
+}
+
+func readFormulatorRecord(value []byte) (*formulatorRecord, error) {
+	fr := &formulatorRecord{}
+	if _, err := fr.ReadFrom(bytes.NewReader(value)); err != nil {
+		return nil, err
+	}
+	return fr, nil
+}
+
+// updateFormulator adds the block to the record of the formulator and keeps the formulator of the height
+func (e *BlockExplorer) updateFormulator(it *indexTxn, b *block.Block, height uint32) error {
+	addr := b.Header.Formulator
+	fr := &formulatorRecord{}
+	value, err := it.Get(formulatorKey(addr))
+	if err != nil {
+		return err
+	}
+	if value != nil {
+		if fr, err = readFormulatorRecord(value); err != nil {
+			return err
+		}
+	}
+	// the records of the old version have no first height
+	if fr.FirstHeight == 0 {
+		fr.FirstHeight = height
+	}
+	fr.Blocks++
+	fr.LastHeight = height
+	if b.Header.TimeoutCount > 0 {
+		fr.TimeoutBlocks++
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := fr.WriteTo(buf); err != nil {
+		return err
+	}
+	if err := it.Set(formulatorKey(addr), buf.Bytes()); err != nil {
+		return err
+	}
+	return it.Set(blockFormulatorKey(height), addr[:])
+}
+
+// formulatorRecordOf returns the block production record of the formulator
+func (e *BlockExplorer) formulatorRecordOf(addr common.Address) (fr *formulatorRecord) {
+	fr = &formulatorRecord{}
+	e.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(formulatorKey(addr))
+		if err != nil {
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		if r, err := readFormulatorRecord(value); err == nil {
+			fr = r
+		}
+		return nil
+	})
+	return
+}
+
+// recentFormulators counts the blocks of each formulator in the recent window
+// it is updated by the indexer with every block and the counts are published by the snapshot
+type recentFormulators struct {
+	heights []uint32
+	addrs   []common.Address
+	counts  map[common.Address]int
+}
+
+// Add counts the block of the height, the blocks out of the window are removed
+func (r *recentFormulators) Add(height uint32, addr common.Address) {
+	if r.counts == nil {
+		r.counts = map[common.Address]int{}
+	}
+	r.heights = append(r.heights, height)
+	r.addrs = append(r.addrs, addr)
+	r.counts[addr]++
+	for len(r.heights) > 0 && r.heights[0]+recentShareWindow <= height {
+		first := r.addrs[0]
+		if r.counts[first]--; r.counts[first] == 0 {
+			delete(r.counts, first)
+		}
+		r.heights = r.heights[1:]
+		r.addrs = r.addrs[1:]
+	}
+}
+
+// Reset removes every counted block
+func (r *recentFormulators) Reset() {
+	r.heights = nil
+	r.addrs = nil
+	r.counts = nil
+}
+
+// Counts returns a copy of the counts and the number of the blocks in the window
+func (r *recentFormulators) Counts() (map[common.Address]int, int) {
+	counts := make(map[common.Address]int, len(r.counts))
+	for addr, c := range r.counts {
+		counts[addr] = c
+	}
+	return counts, len(r.heights)
+}
+
+// rebuildRecentFormulators loads the formulators of the blocks in the recent window after a restart or a rollback
+func (e *BlockExplorer) rebuildRecentFormulators() {
+	e.recents.Reset()
+
+	to := e.indexedHeight
+	var from uint32 = 1
+	if to > recentShareWindow {
+		from = to - recentShareWindow + 1
+	}
+	e.db.View(func(txn *badger.Txn) error {
+		for height := from; height <= to && height > 0; height++ {
+			item, err := txn.Get(blockFormulatorKey(height))
+			if err != nil {
+				continue
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				continue
+			}
+			var addr common.Address
+			copy(addr[:], value)
+			e.recents.Add(height, addr)
+		}
+		return nil
+	})
+}
+
+type formulatorInfo struct {
+	Address       string  `json:"Address"`
+	Name          string  `json:"Name"`
+	Blocks        uint32  `json:"Blocks"`
+	FirstHeight   uint32  `json:"FirstHeight"`
+	LastHeight    uint32  `json:"LastHeight"`
+	TimeoutBlocks uint32  `json:"TimeoutBlocks"`
+	RecentBlocks  int     `json:"RecentBlocks"`
+	RecentShare   float64 `json:"RecentShare"`
+}
+
+type formulatorInfosCase struct {
+	ITotalRecords        int              `json:"iTotalRecords"`
+	ITotalDisplayRecords int              `json:"iTotalDisplayRecords"`
+	SEcho                int              `json:"sEcho"`
+	SColumns             string           `json:"sColumns"`
+	AaData               []formulatorInfo `json:"aaData"`
+}
+
+var formulatorSortFuncs = map[string]func(a, b *formulatorInfo) bool{
+	"blocks":   func(a, b *formulatorInfo) bool { return a.Blocks < b.Blocks },
+	"first":    func(a, b *formulatorInfo) bool { return a.FirstHeight < b.FirstHeight },
+	"last":     func(a, b *formulatorInfo) bool { return a.LastHeight < b.LastHeight },
+	"timeouts": func(a, b *formulatorInfo) bool { return a.TimeoutBlocks < b.TimeoutBlocks },
+	"share":    func(a, b *formulatorInfo) bool { return a.RecentBlocks < b.RecentBlocks },
+}
+
+// formulatorList returns every formulator which made a block sorted by the key
+func (e *BlockExplorer) formulatorList(sortKey string, asc bool) []formulatorInfo {
+	list := []formulatorInfo{}
+	e.db.View(func(txn *badger.Txn) error {
+		snap := e.snapshot()
+		recents, total := snap.RecentFormulators, snap.RecentBlocks
+
+		opts := badger.DefaultIteratorOptions
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		prefix := []byte{formulatorPrefix}
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			var addr common.Address
+			copy(addr[:], item.Key()[len(prefix):])
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			fr, err := readFormulatorRecord(value)
+			if err != nil {
+				continue
+			}
+			fi := formulatorInfo{
+				Address:       addr.String(),
+				Name:          nameOfTxn(txn, addr),
+				Blocks:        fr.Blocks,
+				FirstHeight:   fr.FirstHeight,
+				LastHeight:    fr.LastHeight,
+				TimeoutBlocks: fr.TimeoutBlocks,
+				RecentBlocks:  recents[addr],
+			}
+			if total > 0 {
+				fi.RecentShare = float64(fi.RecentBlocks) * 100 / float64(total)
+			}
+			list = append(list, fi)
+		}
+		return nil
+	})
+
+	less, has := formulatorSortFuncs[sortKey]
+	if !has {
+		less = formulatorSortFuncs["blocks"]
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if asc {
+			return less(&list[i], &list[j])
+		}
+		return less(&list[j], &list[i])
+	})
+	return list
+}
+
+func (e *BlockExplorer) formulators(startStr string, sortKey string, order string) (result formulatorInfosCase, err error) {
+	result.AaData = []formulatorInfo{}
+
+	start, err := parseStart(startStr)
+	if err != nil {
+		return result, err
+	}
+	length := 10
+
+	list := e.formulatorList(sortKey, order == "asc")
+	result.ITotalRecords = len(list)
+	result.ITotalDisplayRecords = len(list)
+	if start < len(list) {
+		end := start + length
+		if end > len(list) {
+			end = len(list)
+		}
+		result.AaData = list[start:end]
+	}
+	return
+}
+
+func init() {
+	registerMigration(2, "formulator records", migrateFormulatorRecords)
+}
+
+// migrateFormulatorRecords converts the block counters of the formulators into the records,
+// the heights of the old blocks are not known so the first height is left as zero
+func migrateFormulatorRecords(db *badger.DB) error {
+	return rewriteKeys(db, func(key []byte, value []byte) ([]byte, []byte, error) {
+		if len(key) == 0 || key[0] != formulatorPrefix || len(value) != 4 {
+			return nil, nil, nil
+		}
+		fr := &formulatorRecord{Blocks: util.BytesToUint32(value)}
+		buf := &bytes.Buffer{}
+		if _, err := fr.WriteTo(buf); err != nil {
+			return nil, nil, err
+		}
+		return key, buf.Bytes(), nil
+	})
+}
@@ -0,0 +1,105 @@
+package blockexplorer
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/core/data"
+	"github.com/labstack/echo"
+)
+
+// errorTemplates is the error page of the http status, the other statuses use the page of 500
+var errorTemplates = map[int]string{
+	http.StatusBadRequest:          "errors/error-3.html",
+	http.StatusNotFound:            "errors/error-4.html",
+	http.StatusInternalServerError: "errors/error-5.html",
+}
+
+// httpStatusOf maps the error of the handler to the http status
+func httpStatusOf(err error) int {
+	if he, ok := err.(*echo.HTTPError); ok {
+		return he.Code
+	}
+	switch err {
+	case ErrNotEnoughParameter, ErrInvalidHeightFormat, ErrInvalidAddressFormat, ErrInvalidStartFormat:
+		return http.StatusBadRequest
+	case ErrNotBlockHash, ErrNotTransactionHash, ErrNotContract, ErrNotExistBlock, ErrUnknownDataOrder, badger.ErrKeyNotFound, data.ErrNotExistAccount:
+		// the heights are checked before the blocks are loaded from the kernel, so only its account error is mapped
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func apiCodeOf(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return apiInvalidParameter
+	case http.StatusNotFound:
+		return apiNotFound
+	default:
+		return apiInternal
+	}
+}
+
+// isJSONPath reports whether the path is served as json so the errors are written as the api error
+func isJSONPath(path string) bool {
+	return strings.HasPrefix(path, "/data/") || strings.HasPrefix(path, "/api/")
+}
+
+// httpErrorHandler writes the error returned by the handlers
+// the data and api routes get the json error object and the pages get the error page of the status
+func (e *BlockExplorer) httpErrorHandler(err error, c echo.Context) {
+	status := httpStatusOf(err)
+	if status >= http.StatusInternalServerError {
+		log.Println(c.Request().URL.String(), err)
+	}
+	if c.Response().Committed {
+		return
+	}
+
+	message := err.Error()
+	if he, ok := err.(*echo.HTTPError); ok {
+		message = fmt.Sprint(he.Message)
+	}
+
+	if isJSONPath(c.Request().URL.Path) {
+		if err := apiFail(c, status, apiCodeOf(status), message); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	if status >= http.StatusInternalServerError {
+		// the internal error is not shown to the visitors
+		message = http.StatusText(http.StatusInternalServerError)
+	}
+	name, has := errorTemplates[status]
+	if !has {
+		name = errorTemplates[http.StatusInternalServerError]
+	}
+	if err := c.Render(status, name, map[string]string{
+		"code":    strconv.Itoa(status),
+		"message": message,
+	}); err != nil {
+		log.Println(err)
+		c.String(status, message)
+	}
+}
+
+// recoverPanic turns the panic of the handler into the internal server error
+func (e *BlockExplorer) recoverPanic(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("panic on %s: %v\n%s", c.Request().URL.String(), r, debug.Stack())
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+		return next(c)
+	}
+}
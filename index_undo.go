@@ -0,0 +1,214 @@
+package blockexplorer
+
+import (
+	"io"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common/util"
+)
+
+type undoEntry struct {
+	Key   []byte
+	Value []byte
+	Exist bool
+}
+
+// indexUndo holds the previous values of the keys written while indexing a block
+type indexUndo struct {
+	TxCount uint32
+	Entries []*undoEntry
+}
+
+// WriteTo is a serialization function
+func (u *indexUndo) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint32(w, u.TxCount); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, uint32(len(u.Entries))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, ue := range u.Entries {
+		if n, err := writeBytes(w, ue.Key); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		exist := []byte{0}
+		if ue.Exist {
+			exist[0] = 1
+		}
+		if n, err := w.Write(exist); err != nil {
+			return wrote, err
+		} else {
+			wrote += int64(n)
+		}
+		if ue.Exist {
+			if n, err := writeBytes(w, ue.Value); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (u *indexUndo) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		u.TxCount = v
+	}
+	Len, n, err := util.ReadUint32(r)
+	if err != nil {
+		return read, err
+	}
+	read += n
+	u.Entries = make([]*undoEntry, 0, Len)
+	for i := uint32(0); i < Len; i++ {
+		ue := &undoEntry{}
+		if bs, n, err := readBytes(r); err != nil {
+			return read, err
+		} else {
+			read += n
+			ue.Key = bs
+		}
+		exist := []byte{0}
+		if n, err := io.ReadFull(r, exist); err != nil {
+			return read, err
+		} else {
+			read += int64(n)
+		}
+		if exist[0] == 1 {
+			ue.Exist = true
+			if bs, n, err := readBytes(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				ue.Value = bs
+			}
+		}
+		u.Entries = append(u.Entries, ue)
+	}
+	return read, nil
+}
+
+func writeBytes(w io.Writer, bs []byte) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint32(w, uint32(len(bs))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := w.Write(bs); err != nil {
+		return wrote, err
+	} else {
+		wrote += int64(n)
+	}
+	return wrote, nil
+}
+
+func readBytes(r io.Reader) ([]byte, int64, error) {
+	var read int64
+	Len, n, err := util.ReadUint32(r)
+	if err != nil {
+		return nil, read, err
+	}
+	read += n
+	bs := make([]byte, Len)
+	if n, err := io.ReadFull(r, bs); err != nil {
+		return nil, read, err
+	} else {
+		read += int64(n)
+	}
+	return bs, read, nil
+}
+
+// indexTxn wraps a badger transaction and records the previous value of every key it writes
+// so the block can be unwound when the chain switches to another branch
+type indexTxn struct {
+	txn     *badger.Txn
+	undo    *indexUndo
+	written map[string]bool
+}
+
+func newIndexTxn(txn *badger.Txn) *indexTxn {
+	return &indexTxn{
+		txn:     txn,
+		undo:    &indexUndo{Entries: []*undoEntry{}},
+		written: map[string]bool{},
+	}
+}
+
+// Get returns the value of the key or nil if the key is not exist
+func (it *indexTxn) Get(key []byte) ([]byte, error) {
+	item, err := it.txn.Get(key)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+// Set writes the value after keeping the previous value of the key
+func (it *indexTxn) Set(key []byte, value []byte) error {
+	if !it.written[string(key)] {
+		item, err := it.txn.Get(key)
+		ue := &undoEntry{Key: append([]byte{}, key...)}
+		if err != nil {
+			if err != badger.ErrKeyNotFound {
+				return err
+			}
+		} else {
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			ue.Exist = true
+			ue.Value = v
+		}
+		it.undo.Entries = append(it.undo.Entries, ue)
+		it.written[string(key)] = true
+	}
+	return it.txn.Set(key, value)
+}
+
+// Increase adds one to the uint32 counter of the key
+func (it *indexTxn) Increase(key []byte) error {
+	value, err := it.Get(key)
+	if err != nil {
+		return err
+	}
+	var count uint32
+	if value != nil {
+		count = util.BytesToUint32(value)
+	}
+	return it.Set(key, util.Uint32ToBytes(count+1))
+}
+
+// restoreUndo puts back the previous values recorded in the undo
+func restoreUndo(txn *badger.Txn, u *indexUndo) error {
+	for i := len(u.Entries) - 1; i >= 0; i-- {
+		ue := u.Entries[i]
+		if ue.Exist {
+			if err := txn.Set(ue.Key, ue.Value); err != nil {
+				return err
+			}
+		} else {
+			if err := txn.Delete(ue.Key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
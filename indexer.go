@@ -0,0 +1,254 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"log"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+)
+
+// indexChunkSize is the maximum number of blocks indexed before the cursor is persisted
+const indexChunkSize = 500
+
+// pollInterval is the fallback interval to check the chain when no block event is arrived
+const pollInterval = 5 * time.Second
+
+// indexBatchSize is the maximum number of blocks written in a db transaction
+const indexBatchSize = 64
+
+// SetIndexWorkers sets the number of workers fetching the blocks in parallel, the number of cpus is used when it is not positive
+func (e *BlockExplorer) SetIndexWorkers(n int) {
+	atomic.StoreInt32(&e.indexWorkers, int32(n))
+}
+
+func (e *BlockExplorer) workerCount() int {
+	if n := int(atomic.LoadInt32(&e.indexWorkers)); n > 0 {
+		return n
+	}
+	return runtime.NumCPU()
+}
+
+// runBackfill indexes the blocks in bounded chunks until the indexer catches up the chain
+// and then waits the block events, the poll interval is kept to catch the missed events
+func (e *BlockExplorer) runBackfill() {
+	for {
+		select {
+		case <-e.closeCh:
+			return
+		default:
+		}
+
+		e.updateChainInfoCount()
+		target := e.source.Provider().Height()
+		if e.indexedHeight >= target {
+			if err := e.checkReorganization(); err != nil {
+				log.Println(err)
+			}
+			select {
+			case <-e.closeCh:
+				return
+			case <-e.blockEvent:
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		to := target
+		if to-e.indexedHeight > indexChunkSize {
+			to = e.indexedHeight + indexChunkSize
+		}
+		if err := e.indexChunk(to); err != nil {
+			log.Println(err)
+			select {
+			case <-e.closeCh:
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// indexChunk indexes the blocks after the cursor to the height and persists the cursor
+// the blocks are fetched by the workers in parallel and committed in the height order by batches
+func (e *BlockExplorer) indexChunk(to uint32) error {
+	if err := e.checkReorganization(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	var commitErr error
+	currentTransactions := 0
+	committed := []*block.Block{} // the last blocks of the chunk for the live feed
+	batch := make([]*block.Block, 0, indexBatchSize)
+	commit := func() {
+		from := e.indexedHeight + 1
+		count, err := e.commitBlocks(from, batch)
+		if err != nil {
+			commitErr = err
+			return
+		}
+		e.chainState.Transactions = count
+		for i, b := range batch {
+			height := from + uint32(i)
+			e.indexedHeight = height
+
+			txCount := len(b.Body.Transactions)
+			currentTransactions += txCount
+			e.recents.Add(height, b.Header.Formulator)
+			if committed = append(committed, b); len(committed) > liveBlocks {
+				committed = committed[1:]
+			}
+		}
+		batch = batch[:0]
+	}
+	for b := range e.fetchBlocks(e.indexedHeight+1, to, done) {
+		batch = append(batch, b)
+		if len(batch) >= indexBatchSize {
+			if commit(); commitErr != nil {
+				break
+			}
+		}
+	}
+	if commitErr == nil && len(batch) > 0 {
+		commit()
+	}
+
+	e.chainState.currentTransactions = currentTransactions
+	e.publish()
+	e.pushBlocks(committed, e.indexedHeight-uint32(len(committed))+1)
+	e.pushChainInfo()
+
+	if err := e.saveCursor(); err != nil {
+		return err
+	}
+	return commitErr
+}
+
+// fetchBlocks loads the blocks of the range by the workers and sends them in the height order
+// the channel is closed at the end of the range, at the first block which can not be loaded or when done is closed
+func (e *BlockExplorer) fetchBlocks(from uint32, to uint32, done <-chan struct{}) <-chan *block.Block {
+	out := make(chan *block.Block, indexBatchSize)
+	if to < from {
+		close(out)
+		return out
+	}
+
+	count := int(to - from + 1)
+	slots := make([]chan *block.Block, count)
+	for i := range slots {
+		slots[i] = make(chan *block.Block, 1)
+	}
+	quit := make(chan struct{})
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := 0; i < count; i++ {
+			select {
+			case jobs <- i:
+			case <-quit:
+				return
+			}
+		}
+	}()
+	for w := 0; w < e.workerCount(); w++ {
+		go func() {
+			for i := range jobs {
+				b, err := e.source.Block(from + uint32(i))
+				if err != nil {
+					b = nil
+				}
+				slots[i] <- b
+			}
+		}()
+	}
+	go func() {
+		defer close(out)
+		defer close(quit)
+		for _, slot := range slots {
+			var b *block.Block
+			select {
+			case b = <-slot:
+			case <-done:
+				return
+			}
+			if b == nil {
+				return
+			}
+			select {
+			case out <- b:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// commitBlocks indexes the blocks from the height and moves the cursor to the last block in a db transaction
+// it returns the number of the indexed transactions, the batch is split in halves when it is too big for a transaction
+// the tps of the blocks is measured by a copy of the meter which replaces it when the transaction is committed
+// the blocks are written by a transaction instead of the batch writer because the counters and the undo
+// read the values written by the previous blocks of the batch, which the batch writer can not read back
+func (e *BlockExplorer) commitBlocks(from uint32, blocks []*block.Block) (int, error) {
+	var count int
+	tps := e.tps.Clone()
+	err := e.db.Update(func(txn *badger.Txn) error {
+		for i, b := range blocks {
+			if err := e.updateBlock(txn, b, from+uint32(i), tps); err != nil {
+				return err
+			}
+		}
+		var err error
+		count, err = e.writeProgress(txn, from+uint32(len(blocks))-1)
+		return err
+	})
+	if err == badger.ErrTxnTooBig && len(blocks) > 1 {
+		half := len(blocks) / 2
+		if _, err := e.commitBlocks(from, blocks[:half]); err != nil {
+			return 0, err
+		}
+		return e.commitBlocks(from+uint32(half), blocks[half:])
+	}
+	if err == nil {
+		e.tps = tps
+	}
+	return count, err
+}
+
+// writeProgress stores the cursor and the chain info in the transaction of the index writes,
+// so the cursor never falls behind the indexed blocks after a crash
+// the total of the transactions is taken from the transaction sequence of the txn and returned
+func (e *BlockExplorer) writeProgress(txn *badger.Txn, height uint32) (int, error) {
+	count, err := getTxCount(txn)
+	if err != nil {
+		return 0, err
+	}
+	info := e.chainState
+	info.Transactions = int(count)
+	buf := &bytes.Buffer{}
+	if _, err := info.WriteTo(buf); err != nil {
+		return 0, err
+	}
+	if err := txn.Set(blockChainInfoKey, buf.Bytes()); err != nil {
+		return 0, err
+	}
+	if err := txn.Set(indexCursorKey, util.Uint32ToBytes(height)); err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// saveCursor stores the cursor with the chain info
+func (e *BlockExplorer) saveCursor() error {
+	return e.db.Update(func(txn *badger.Txn) error {
+		_, err := e.writeProgress(txn, e.indexedHeight)
+		return err
+	})
+}
@@ -0,0 +1,119 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/framework/chain"
+)
+
+// indexCounters reads the counters which are increased by every indexed block
+func indexCounters(t *testing.T, db *badger.DB, b *block.Block) [][]byte {
+	keys := [][]byte{
+		formulatorKey(b.Header.Formulator),
+		chainKey(b.Header.ChainCoord),
+		rollupKey(rollupMinute, rollupBucket(rollupMinute, b.Header.Timestamp())),
+		rollupKey(rollupDay, rollupBucket(rollupDay, b.Header.Timestamp())),
+		txCountKey,
+		indexCursorKey,
+	}
+	values := [][]byte{}
+	if err := db.View(func(txn *badger.Txn) error {
+		for _, key := range keys {
+			item, err := txn.Get(key)
+			if err != nil {
+				return err
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func TestReplayedBlockIsCountedOnce(t *testing.T) {
+	db, closeDB := openTestDB(t)
+	defer closeDB()
+
+	e := &BlockExplorer{
+		db:  db,
+		tps: newTpsMeter(),
+	}
+	b := &block.Block{
+		Header: &block.Header{
+			Base: chain.Base{
+				Height_:    1,
+				Timestamp_: 1552440000000000000,
+			},
+			ChainCoord: common.NewCoordinate(0, 0),
+			Formulator: common.NewAddress(common.NewCoordinate(0, 1), 0),
+		},
+		Body: &block.Body{},
+	}
+	if _, err := e.commitBlocks(1, []*block.Block{b}); err != nil {
+		t.Fatal(err)
+	}
+	indexed := indexCounters(t, db, b)
+	if fr, err := readFormulatorRecord(indexed[0]); err != nil || fr.Blocks != 1 {
+		t.Fatalf("formulator record is not indexed: %v", err)
+	}
+
+	if _, err := e.commitBlocks(1, []*block.Block{b}); err != nil {
+		t.Fatal(err)
+	}
+	for i, value := range indexCounters(t, db, b) {
+		if !bytes.Equal(value, indexed[i]) {
+			t.Errorf("counter %d is %x after the replay, expected %x", i, value, indexed[i])
+		}
+	}
+
+	// the old version only kept the hashes and the block count of the formulator
+	oldDB, closeOldDB := openTestDB(t)
+	defer closeOldDB()
+	if err := oldDB.Update(func(txn *badger.Txn) error {
+		if err := txn.Set(blockHashKey(b.Header.Hash()), util.Uint32ToBytes(1)); err != nil {
+			return err
+		}
+		return txn.Set(formulatorKey(b.Header.Formulator), formulatorRecordBytes(1))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	old := &BlockExplorer{
+		db:  oldDB,
+		tps: newTpsMeter(),
+	}
+	if _, err := old.commitBlocks(1, []*block.Block{b}); err != nil {
+		t.Fatal(err)
+	}
+	for i, value := range indexCounters(t, oldDB, b) {
+		if !bytes.Equal(value, indexed[i]) {
+			t.Errorf("counter %d is %x after indexing the block of the old version, expected %x", i, value, indexed[i])
+		}
+	}
+	if err := db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(indexCursorKey)
+		if err != nil {
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		if height := util.BytesToUint32(value); height != 1 {
+			t.Errorf("cursor is %d, expected 1", height)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
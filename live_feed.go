@@ -0,0 +1,384 @@
+package blockexplorer
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/block"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo"
+)
+
+// live feed topics, the type and the address topics are followed by the transaction type name and the address
+// the transaction topics send the transactions of a block in a message
+const (
+	topicBlocks    = "blocks"
+	topicTxs       = "txs"
+	topicChainInfo = "chainInfo"
+	topicTxType    = "txs:type:"
+	topicTxAddress = "txs:address:"
+)
+
+// live feed limits
+const (
+	liveSendBuffer = 256
+	liveBlocks     = 8 // the recent blocks pushed when the index reaches the tip
+	liveBlockTxs   = 8 // the recent transactions in the message of a block
+	liveMaxTopics  = 64
+	liveReadLimit  = 4096
+	liveWriteWait  = 10 * time.Second
+	livePongWait   = 60 * time.Second
+	livePingPeriod = livePongWait * 9 / 10
+)
+
+var liveUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// liveBlock is the row of the recent blocks with the recent transactions of the block,
+// so the dashboard renders them without loading the data
+type liveBlock struct {
+	blockInfos
+	Transactions []txInfos `json:"Transactions"`
+}
+
+type liveTx struct {
+	Hash        string `json:"hash"`
+	Type        string `json:"type"`
+	Timestamp   string `json:"timestamp"`
+	BlockHeight uint32 `json:"blockHeight"`
+	Index       uint32 `json:"index"`
+}
+
+// liveMessage is sent to the clients, it has the data of the topic or the error of the request
+type liveMessage struct {
+	Topic string      `json:"topic,omitempty"`
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// liveRequest is sent by the clients to change the topics, the action is subscribe or unsubscribe
+type liveRequest struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics"`
+}
+
+type liveClient struct {
+	conn   *websocket.Conn
+	send   chan []byte
+	topics map[string]bool
+}
+
+// liveFeed fans out the indexed blocks to the websocket clients
+// the messages are queued to the buffer of each client without blocking,
+// a client which can not take the message is disconnected so it never stalls the indexer
+type liveFeed struct {
+	sync.RWMutex
+	clients map[*liveClient]bool
+}
+
+func newLiveFeed() *liveFeed {
+	return &liveFeed{
+		clients: map[*liveClient]bool{},
+	}
+}
+
+// normalizeTopic validates the topic and returns it in the canonical form
+func normalizeTopic(topic string) (string, error) {
+	switch {
+	case topic == topicBlocks, topic == topicTxs, topic == topicChainInfo:
+		return topic, nil
+	case strings.HasPrefix(topic, topicTxType) && len(topic) > len(topicTxType):
+		return topic, nil
+	case strings.HasPrefix(topic, topicTxAddress):
+		addr, err := common.ParseAddress(topic[len(topicTxAddress):])
+		if err != nil {
+			return "", ErrInvalidAddressFormat
+		}
+		return topicTxAddress + addr.String(), nil
+	}
+	return "", ErrUnknownLiveTopic
+}
+
+func (f *liveFeed) add(cl *liveClient) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.clients[cl] = true
+}
+
+// remove disconnects the client, the send channel is closed in the lock so the broadcast never sends to it
+func (f *liveFeed) remove(cl *liveClient) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.clients[cl] {
+		delete(f.clients, cl)
+		close(cl.send)
+	}
+}
+
+func (f *liveFeed) closeAll() {
+	f.Lock()
+	defer f.Unlock()
+
+	for cl := range f.clients {
+		delete(f.clients, cl)
+		close(cl.send)
+	}
+}
+
+// subscribe adds or removes the topics of the client
+func (f *liveFeed) subscribe(cl *liveClient, topics []string, on bool) error {
+	f.Lock()
+	defer f.Unlock()
+
+	for _, t := range topics {
+		topic, err := normalizeTopic(strings.TrimSpace(t))
+		if err != nil {
+			return err
+		}
+		if !on {
+			delete(cl.topics, topic)
+			continue
+		}
+		if !cl.topics[topic] && len(cl.topics) >= liveMaxTopics {
+			return ErrTooManyLiveTopics
+		}
+		cl.topics[topic] = true
+	}
+	return nil
+}
+
+// hasClients reports whether any client is connected so the events are not built for nobody
+func (f *liveFeed) hasClients() bool {
+	f.RLock()
+	defer f.RUnlock()
+
+	return len(f.clients) > 0
+}
+
+// watching reports whether any client subscribes a topic which starts with the prefix
+func (f *liveFeed) watching(prefix string) bool {
+	f.RLock()
+	defer f.RUnlock()
+
+	for cl := range f.clients {
+		for topic := range cl.topics {
+			if strings.HasPrefix(topic, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// broadcast queues the data to the subscribers of the topic and disconnects the clients whose buffer is full
+func (f *liveFeed) broadcast(topic string, data interface{}) {
+	var bs []byte
+	slow := []*liveClient{}
+	f.RLock()
+	for cl := range f.clients {
+		if !cl.topics[topic] {
+			continue
+		}
+		if bs == nil {
+			var err error
+			bs, err = json.Marshal(&liveMessage{Topic: topic, Data: data})
+			if err != nil {
+				f.RUnlock()
+				return
+			}
+		}
+		select {
+		case cl.send <- bs:
+		default:
+			slow = append(slow, cl)
+		}
+	}
+	f.RUnlock()
+
+	for _, cl := range slow {
+		f.remove(cl)
+	}
+}
+
+// reply queues the message to the client, it is dropped when the buffer is full
+func (f *liveFeed) reply(cl *liveClient, m *liveMessage) {
+	bs, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	f.RLock()
+	defer f.RUnlock()
+
+	if f.clients[cl] {
+		select {
+		case cl.send <- bs:
+		default:
+		}
+	}
+}
+
+func (f *liveFeed) readPump(cl *liveClient) {
+	defer f.remove(cl)
+
+	cl.conn.SetReadLimit(liveReadLimit)
+	cl.conn.SetReadDeadline(time.Now().Add(livePongWait))
+	cl.conn.SetPongHandler(func(string) error {
+		return cl.conn.SetReadDeadline(time.Now().Add(livePongWait))
+	})
+	for {
+		_, bs, err := cl.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req liveRequest
+		if err := json.Unmarshal(bs, &req); err != nil {
+			f.reply(cl, &liveMessage{Error: err.Error()})
+			continue
+		}
+		switch req.Action {
+		case "subscribe":
+			err = f.subscribe(cl, req.Topics, true)
+		case "unsubscribe":
+			err = f.subscribe(cl, req.Topics, false)
+		default:
+			err = ErrUnknownLiveAction
+		}
+		if err != nil {
+			f.reply(cl, &liveMessage{Error: err.Error()})
+		}
+	}
+}
+
+func (f *liveFeed) writePump(cl *liveClient) {
+	ticker := time.NewTicker(livePingPeriod)
+	defer func() {
+		ticker.Stop()
+		cl.conn.Close()
+	}()
+	for {
+		select {
+		case bs, ok := <-cl.send:
+			cl.conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			if !ok {
+				cl.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := cl.conn.WriteMessage(websocket.TextMessage, bs); err != nil {
+				return
+			}
+		case <-ticker.C:
+			cl.conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			if err := cl.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// liveHandler upgrades the connection to the websocket of the live feed
+// the topics are given by the topics query as the comma separated list or by the subscribe requests
+func (e *BlockExplorer) liveHandler(c echo.Context) error {
+	conn, err := liveUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// the upgrader has written the error response
+		return nil
+	}
+	cl := &liveClient{
+		conn:   conn,
+		send:   make(chan []byte, liveSendBuffer),
+		topics: map[string]bool{},
+	}
+	e.feed.add(cl)
+	if q := c.QueryParam("topics"); q != "" {
+		if err := e.feed.subscribe(cl, strings.Split(q, ","), true); err != nil {
+			e.feed.reply(cl, &liveMessage{Error: err.Error()})
+		}
+	}
+
+	go e.feed.writePump(cl)
+	e.feed.readPump(cl)
+	return nil
+}
+
+// pushBlocks sends the recent blocks of the indexed blocks when the index reaches the tip of the chain,
+// the blocks indexed behind the tip are not sent so the backfill never floods the clients
+func (e *BlockExplorer) pushBlocks(blocks []*block.Block, from uint32) {
+	if !e.feed.hasClients() || e.indexedHeight < e.Kernel.Provider().Height() {
+		return
+	}
+	for i, b := range blocks {
+		e.pushBlock(b, from+uint32(i))
+	}
+}
+
+// pushBlock sends the indexed block and its transactions to the subscribers
+// the transactions of the block are sent in a message per topic so a busy block does not fill the buffers
+func (e *BlockExplorer) pushBlock(b *block.Block, height uint32) {
+	info, err := e.blockInfoOf(height)
+	if err != nil {
+		return
+	}
+	lb := &liveBlock{
+		blockInfos:   info,
+		Transactions: []txInfos{},
+	}
+	txs := b.Body.Transactions
+	for i := len(txs) - 1; i >= 0 && len(lb.Transactions) < liveBlockTxs; i-- {
+		name, _ := e.Kernel.Transactor().NameByType(txs[i].Type())
+		lb.Transactions = append(lb.Transactions, txInfos{
+			TxHash:    txs[i].Hash().String(),
+			BlockHash: info.BlockHash,
+			ChainID:   b.Header.ChainCoord.String(),
+			Time:      txs[i].Timestamp(),
+			TxType:    name,
+		})
+	}
+	e.feed.broadcast(topicBlocks, lb)
+
+	// the addresses are found by the reflection so they are collected only when they are watched
+	watchTxs := e.feed.watching(topicTxs)
+	watchAddress := e.feed.watching(topicTxAddress)
+	if !watchTxs || len(txs) == 0 {
+		return
+	}
+	topics := map[string][]*liveTx{}
+	for i, tx := range txs {
+		lt := &liveTx{
+			Hash:        tx.Hash().String(),
+			Timestamp:   apiTime(tx.Timestamp()),
+			BlockHeight: height,
+			Index:       uint32(i),
+		}
+		if name, err := e.Kernel.Transactor().NameByType(tx.Type()); err == nil {
+			lt.Type = name
+		}
+		topics[topicTxs] = append(topics[topicTxs], lt)
+		if lt.Type != "" {
+			topics[topicTxType+lt.Type] = append(topics[topicTxType+lt.Type], lt)
+		}
+		if watchAddress {
+			for _, addr := range e.txAddresses(tx, height, uint32(i)) {
+				topic := topicTxAddress + addr.String()
+				topics[topic] = append(topics[topic], lt)
+			}
+		}
+	}
+	for topic, lts := range topics {
+		e.feed.broadcast(topic, lts)
+	}
+}
+
+// pushChainInfo sends the published chain info to the subscribers
+func (e *BlockExplorer) pushChainInfo() {
+	if !e.feed.hasClients() {
+		return
+	}
+	e.feed.broadcast(topicChainInfo, e.apiChainInfoOf())
+}
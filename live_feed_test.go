@@ -0,0 +1,41 @@
+package blockexplorer
+
+import (
+	"testing"
+)
+
+func TestLiveFeedDropsSlowClient(t *testing.T) {
+	f := newLiveFeed()
+	fast := &liveClient{send: make(chan []byte, liveSendBuffer), topics: map[string]bool{}}
+	slow := &liveClient{send: make(chan []byte, 1), topics: map[string]bool{}}
+	other := &liveClient{send: make(chan []byte, 1), topics: map[string]bool{}}
+	for _, cl := range []*liveClient{fast, slow, other} {
+		f.add(cl)
+	}
+	if err := f.subscribe(fast, []string{topicBlocks}, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.subscribe(slow, []string{topicBlocks}, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.subscribe(other, []string{topicTxs}, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.subscribe(other, []string{"unknown"}, true); err != ErrUnknownLiveTopic {
+		t.Fatalf("unknown topic is subscribed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		f.broadcast(topicBlocks, &liveBlock{blockInfos: blockInfos{BlockHeight: uint32(i)}})
+	}
+
+	if len(fast.send) != 3 {
+		t.Errorf("fast client has %d messages", len(fast.send))
+	}
+	if f.clients[slow] {
+		t.Error("slow client is not disconnected")
+	}
+	if len(other.send) != 0 || !f.clients[other] {
+		t.Error("the client of the other topic is touched")
+	}
+}
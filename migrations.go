@@ -0,0 +1,230 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sort"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+)
+
+// ErrUnsupportedSchemaVersion is returned when the db is written by a newer explorer
+var ErrUnsupportedSchemaVersion = errors.New("Db schema version is newer than this explorer supports, upgrade the explorer or use another db path")
+
+// migrationBatchSize is the maximum size of the entries rewritten in a transaction
+const migrationBatchSize = 4 << 20
+
+// migration upgrades the db from the previous schema version to the Version
+type migration struct {
+	Version uint32
+	Name    string
+	Migrate func(db *badger.DB) error
+}
+
+var migrations = []*migration{}
+
+// registerMigration adds the migration to the registry, versions should be increased one by one
+func registerMigration(version uint32, name string, fn func(db *badger.DB) error) {
+	for _, m := range migrations {
+		if m.Version == version {
+			panic("explorer: duplicated migration version " + name)
+		}
+	}
+	migrations = append(migrations, &migration{
+		Version: version,
+		Name:    name,
+		Migrate: fn,
+	})
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+}
+
+func init() {
+	registerMigration(1, "namespaced keys", migrateNamespacedKeys)
+}
+
+// latestSchemaVersion returns the version of the db written by this explorer
+func latestSchemaVersion() uint32 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+func getSchemaVersion(db *badger.DB) (version uint32, empty bool, err error) {
+	err = db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(schemaVersionKey)
+		if err != nil {
+			if err != badger.ErrKeyNotFound {
+				return err
+			}
+			opts := badger.DefaultIteratorOptions
+			opts.PrefetchValues = false
+			it := txn.NewIterator(opts)
+			defer it.Close()
+			it.Rewind()
+			empty = !it.Valid()
+			return nil
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		version = util.BytesToUint32(value)
+		return nil
+	})
+	return
+}
+
+func setSchemaVersion(db *badger.DB, version uint32) error {
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Set(schemaVersionKey, util.Uint32ToBytes(version))
+	})
+}
+
+// runMigrations upgrades the db to the latest schema version
+func runMigrations(db *badger.DB) error {
+	version, empty, err := getSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	latest := latestSchemaVersion()
+	if empty {
+		return setSchemaVersion(db, latest)
+	}
+	if version > latest {
+		log.Println("explorer: db schema version", version, "is newer than the supported version", latest)
+		return ErrUnsupportedSchemaVersion
+	}
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		log.Println("explorer: migrating db schema to version", m.Version, "("+m.Name+")")
+		if err := m.Migrate(db); err != nil {
+			return err
+		}
+		if err := setSchemaVersion(db, m.Version); err != nil {
+			return err
+		}
+		version = m.Version
+	}
+	return nil
+}
+
+type rewriteEntry struct {
+	oldKey []byte
+	newKey []byte
+	value  []byte
+}
+
+// rewriteKeys moves every key that the convert function returns a new key for
+// the keys are read from a snapshot and written in bounded transactions
+func rewriteKeys(db *badger.DB, convert func(key []byte, value []byte) ([]byte, []byte, error)) error {
+	batch := []*rewriteEntry{}
+	size := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := db.Update(func(txn *badger.Txn) error {
+			for _, re := range batch {
+				if err := txn.Delete(re.oldKey); err != nil {
+					return err
+				}
+				if err := txn.Set(re.newKey, re.value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		batch = []*rewriteEntry{}
+		size = 0
+		return nil
+	}
+
+	if err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			key := item.KeyCopy(nil)
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			newKey, newValue, err := convert(key, value)
+			if err != nil {
+				return err
+			}
+			if newKey == nil {
+				continue
+			}
+			batch = append(batch, &rewriteEntry{
+				oldKey: key,
+				newKey: newKey,
+				value:  newValue,
+			})
+			size += len(key) + len(newKey) + len(newValue)
+			if size >= migrationBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return flush()
+}
+
+var hashSize = len(hash.Hash256{})
+
+// namespacedKey returns the version 1 key of the flat key or nil if the key is not a flat key
+func namespacedKey(key []byte) []byte {
+	switch {
+	case string(key) == "blockChainInfo":
+		return blockChainInfoKey
+	case string(key) == "MaximumTps":
+		return maximumTpsKey
+	case bytes.HasPrefix(key, []byte("formulator")):
+		addr, err := common.ParseAddress(string(key[len("formulator"):]))
+		if err != nil {
+			return nil
+		}
+		return formulatorKey(addr)
+	case len(key) == hashSize*2:
+		bs, err := hex.DecodeString(string(key))
+		if err != nil {
+			return nil
+		}
+		var h hash.Hash256
+		copy(h[:], bs)
+		return blockHashKey(h)
+	case len(key) == hashSize:
+		var h hash.Hash256
+		copy(h[:], key)
+		return txHashKey(h)
+	}
+	return nil
+}
+
+// migrateNamespacedKeys moves the flat keys of the version 0 into the namespaces of the index types
+func migrateNamespacedKeys(db *badger.DB) error {
+	return rewriteKeys(db, func(key []byte, value []byte) ([]byte, []byte, error) {
+		newKey := namespacedKey(key)
+		if newKey == nil {
+			return nil, nil, nil
+		}
+		return newKey, value, nil
+	})
+}
@@ -0,0 +1,123 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+)
+
+func openTestDB(t *testing.T) (*badger.DB, func()) {
+	dir, err := ioutil.TempDir("", "explorer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := badger.DefaultOptions
+	opts.Dir = dir
+	opts.ValueDir = dir
+	db, err := badger.Open(opts)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestMigrateNamespacedKeys(t *testing.T) {
+	db, closeDB := openTestDB(t)
+	defer closeDB()
+
+	blockHash := hash.Hash([]byte("block"))
+	txHash := hash.Hash([]byte("tx"))
+	addr := common.NewAddress(common.NewCoordinate(1, 0), 0)
+
+	flat := map[string][]byte{
+		blockHash.String():           util.Uint32ToBytes(1),
+		string(txHash[:]):            util.Uint64ToBytes(1 << 32),
+		"formulator" + addr.String(): util.Uint32ToBytes(2),
+		"blockChainInfo":             []byte{1, 2, 3},
+		"MaximumTps":                 util.Uint32ToBytes(7),
+	}
+	if err := db.Update(func(txn *badger.Txn) error {
+		for k, v := range flat {
+			if err := txn.Set([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+
+	peaks := newTpsMeter()
+	peaks.windows[tpsWindows[0].Name].peak = &tpsPeak{Tps: 7 / tpsWindows[0].Duration.Seconds()}
+	peaksBuf := &bytes.Buffer{}
+	peaks.WriteTo(peaksBuf)
+
+	expected := map[string][]byte{
+		string(blockHashKey(blockHash)): util.Uint32ToBytes(1),
+		string(txHashKey(txHash)):       util.Uint64ToBytes(1 << 32),
+		string(formulatorKey(addr)):     formulatorRecordBytes(2),
+		string(blockChainInfoKey):       []byte{1, 2, 3},
+		string(tpsPeaksKey):             peaksBuf.Bytes(),
+		string(schemaVersionKey):        util.Uint32ToBytes(latestSchemaVersion()),
+	}
+	if err := db.View(func(txn *badger.Txn) error {
+		count := 0
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			count++
+			key := string(it.Item().Key())
+			v, has := expected[key]
+			if !has {
+				t.Errorf("unexpected key %x", key)
+				continue
+			}
+			value, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(value, v) {
+				t.Errorf("value of %x is %x, expected %x", key, value, v)
+			}
+		}
+		if count != len(expected) {
+			t.Errorf("db has %d keys, expected %d", count, len(expected))
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func formulatorRecordBytes(blocks uint32) []byte {
+	buf := &bytes.Buffer{}
+	fr := &formulatorRecord{Blocks: blocks}
+	fr.WriteTo(buf)
+	return buf.Bytes()
+}
+
+func TestRunMigrationsRefusesNewerVersion(t *testing.T) {
+	db, closeDB := openTestDB(t)
+	defer closeDB()
+
+	if err := setSchemaVersion(db, latestSchemaVersion()+1); err != nil {
+		t.Fatal(err)
+	}
+	if err := runMigrations(db); err != ErrUnsupportedSchemaVersion {
+		t.Errorf("expected ErrUnsupportedSchemaVersion, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package blockexplorer
+
+import (
+	"reflect"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+)
+
+// genesisNameBatch is the number of the genesis accounts written in a db transaction
+const genesisNameBatch = 1000
+
+// IndexGenesis registers the names of the accounts created by the genesis context data
+// the genesis is not in the blocks so it is given to NewBlockExplorerWithGenesis, it is skipped when it is already registered
+func (e *BlockExplorer) IndexGenesis(ctd *data.ContextData) error {
+	done := false
+	if err := e.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(genesisNamesKey)
+		if err == nil {
+			done = true
+			return nil
+		}
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+	if done {
+		return nil
+	}
+
+	type accountName struct {
+		addr common.Address
+		name string
+	}
+	names := []accountName{}
+	for addr, acc := range ctd.CreatedAccountMap {
+		if a, ok := acc.(interface{ Name() string }); ok && a.Name() != "" {
+			names = append(names, accountName{addr: addr, name: a.Name()})
+		}
+	}
+
+	for len(names) > 0 {
+		batch := names
+		if len(batch) > genesisNameBatch {
+			batch = batch[:genesisNameBatch]
+		}
+		names = names[len(batch):]
+		if err := e.db.Update(func(txn *badger.Txn) error {
+			for _, an := range batch {
+				if err := txn.Set(nameKey(an.name), an.addr[:]); err != nil {
+					return err
+				}
+				if err := txn.Set(addressNameKey(an.addr), []byte(an.name)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return e.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(genesisNamesKey, []byte{1})
+	})
+}
+
+// txAccountName returns the name given to the account created by the transaction
+func txAccountName(v reflect.Value) string {
+	name := ""
+	walkFields(v, func(f reflect.StructField, fv reflect.Value) bool {
+		if (f.Name == "Name" || f.Name == "Name_") && f.Type.Kind() == reflect.String {
+			name = fv.String()
+			return false
+		}
+		return true
+	})
+	return name
+}
+
+// updateNames registers the names of the accounts created by the transactions of the block
+func (e *BlockExplorer) updateNames(it *indexTxn, b *block.Block, height uint32) error {
+	for i, tx := range b.Body.Transactions {
+		name, err := e.source.Transactor().NameByType(tx.Type())
+		if err != nil || !accountCreateTxTypes[name] {
+			continue
+		}
+		accName := txAccountName(reflect.ValueOf(tx))
+		if accName == "" {
+			continue
+		}
+		addr := common.NewAddress(common.NewCoordinate(height, uint16(i)), 0)
+		if err := it.Set(nameKey(accName), addr[:]); err != nil {
+			return err
+		}
+		if err := it.Set(addressNameKey(addr), []byte(accName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nameOfTxn(txn *badger.Txn, addr common.Address) string {
+	item, err := txn.Get(addressNameKey(addr))
+	if err != nil {
+		return ""
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return ""
+	}
+	return string(value)
+}
+
+// nameOf returns the registered name of the address or an empty string
+func (e *BlockExplorer) nameOf(addr common.Address) (name string) {
+	e.db.View(func(txn *badger.Txn) error {
+		name = nameOfTxn(txn, addr)
+		return nil
+	})
+	return
+}
+
+// namesOf returns the registered names of the addresses which have a name
+func (e *BlockExplorer) namesOf(addrs []common.Address) map[string]string {
+	names := map[string]string{}
+	e.db.View(func(txn *badger.Txn) error {
+		for _, addr := range addrs {
+			if name := nameOfTxn(txn, addr); name != "" {
+				names[addr.String()] = name
+			}
+		}
+		return nil
+	})
+	return names
+}
+
+// registeredAddress returns the address of the registered name
+func (e *BlockExplorer) registeredAddress(name string) (addr common.Address, has bool) {
+	e.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(nameKey(name))
+		if err != nil {
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		copy(addr[:], value)
+		has = true
+		return nil
+	})
+	return
+}
@@ -0,0 +1,338 @@
+package blockexplorer
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// openapiPath is the path of the generated OpenAPI document
+const openapiPath = "/api/openapi.json"
+
+// APIParameter is a parameter of a route in the OpenAPI document
+type APIParameter struct {
+	Name        string
+	In          string // query as default, path or header
+	Type        string // string as default, integer, number or boolean
+	Description string
+	Required    bool
+}
+
+// APIDoc is the OpenAPI description of a route added by AddURL or RegisterDataHandler
+// the schema of the response is generated from the type of Response, Schema is used as it is when Response is nil
+type APIDoc struct {
+	Summary     string
+	Parameters  []APIParameter
+	Response    interface{}
+	Schema      map[string]interface{}
+	ContentType string // application/json as default
+}
+
+type apiRoute struct {
+	Method string
+	Path   string
+	Doc    *APIDoc
+}
+
+type openapiDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openapiInfo                             `json:"info"`
+	Paths      map[string]map[string]*openapiOperation `json:"paths"`
+	Components openapiComponents                       `json:"components"`
+}
+
+type openapiInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openapiComponents struct {
+	Schemas map[string]*openapiSchema `json:"schemas"`
+}
+
+type openapiOperation struct {
+	Summary    string                      `json:"summary,omitempty"`
+	Parameters []*openapiParameter         `json:"parameters,omitempty"`
+	Responses  map[string]*openapiResponse `json:"responses"`
+}
+
+type openapiParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *openapiSchema `json:"schema"`
+}
+
+type openapiResponse struct {
+	Description string                  `json:"description"`
+	Content     map[string]openapiMedia `json:"content,omitempty"`
+}
+
+type openapiMedia struct {
+	Schema interface{} `json:"schema"`
+}
+
+type openapiSchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *openapiSchema            `json:"items,omitempty"`
+	Properties           map[string]*openapiSchema `json:"properties,omitempty"`
+	AdditionalProperties *openapiSchema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// openapiSchemas generates the schemas of the go types, the structs are put into the components by their type names
+type openapiSchemas struct {
+	components map[string]*openapiSchema
+	names      map[reflect.Type]string
+}
+
+func newOpenapiSchemas() *openapiSchemas {
+	return &openapiSchemas{
+		components: map[string]*openapiSchema{},
+		names:      map[reflect.Type]string{},
+	}
+}
+
+// schemaOf returns the schema of the type as encoding/json writes it
+func (ss *openapiSchemas) schemaOf(t reflect.Type) *openapiSchema {
+	switch {
+	case t == rawMessageType:
+		return &openapiSchema{}
+	case t == timeType:
+		return &openapiSchema{Type: "string", Format: "date-time"}
+	case t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface && (t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)):
+		// the common types are written as their strings
+		return &openapiSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := ss.schemaOf(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.Interface:
+		return &openapiSchema{}
+	case reflect.Bool:
+		return &openapiSchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &openapiSchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &openapiSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &openapiSchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openapiSchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openapiSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openapiSchema{Type: "string", Format: "byte"}
+		}
+		return &openapiSchema{Type: "array", Items: ss.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &openapiSchema{Type: "object", AdditionalProperties: ss.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return ss.structRef(t)
+	}
+	return &openapiSchema{}
+}
+
+func (ss *openapiSchemas) structRef(t reflect.Type) *openapiSchema {
+	name, has := ss.names[t]
+	if !has {
+		name = t.Name()
+		if name == "" {
+			name = "object"
+		}
+		for i := 2; ss.components[name] != nil; i++ {
+			name = t.Name() + strconv.Itoa(i)
+		}
+		ss.names[t] = name
+		// registered before the fields for the recursive types
+		s := &openapiSchema{Type: "object", Properties: map[string]*openapiSchema{}}
+		ss.components[name] = s
+		ss.addFields(s, t)
+	}
+	return &openapiSchema{Ref: "#/components/schemas/" + name}
+}
+
+func (ss *openapiSchemas) addFields(s *openapiSchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// the fields of the embedded struct are promoted
+				ss.addFields(s, ft)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = ss.schemaOf(f.Type)
+	}
+}
+
+// openapiPathOf converts the echo path parameters to the OpenAPI path template
+func openapiPathOf(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") {
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func queryParams(names ...string) []APIParameter {
+	params := make([]APIParameter, 0, len(names))
+	for _, name := range names {
+		params = append(params, APIParameter{Name: name})
+	}
+	return params
+}
+
+// pageDoc is the description of a page which takes the query parameters
+func pageDoc(summary string, params ...string) *APIDoc {
+	return &APIDoc{Summary: summary, Parameters: queryParams(params...), ContentType: "text/html"}
+}
+
+// openapiDocument generates the OpenAPI document of the routes registered with their descriptions and the data handlers
+func (e *BlockExplorer) openapiDocument() *openapiDocument {
+	doc := &openapiDocument{
+		OpenAPI: "3.0.0",
+		Info: openapiInfo{
+			Title:   "FLETA Block Explorer",
+			Version: "1",
+		},
+		Paths: map[string]map[string]*openapiOperation{},
+	}
+	schemas := newOpenapiSchemas()
+	errorSchema := schemas.schemaOf(reflect.TypeOf(apiError{}))
+
+	routes := []apiRoute{}
+	for _, h := range e.dataHandlerList() {
+		method := h.Method
+		if method == "" {
+			method = "GET"
+		}
+		doc := h.Doc
+		if doc == nil {
+			doc = &APIDoc{}
+		}
+		routes = append(routes, apiRoute{method, "/data/" + h.Order, doc})
+	}
+	e.apiDocsLock.Lock()
+	routes = append(routes, e.apiRoutes...)
+	e.apiDocsLock.Unlock()
+
+	for _, r := range routes {
+		op := &openapiOperation{
+			Summary:   r.Doc.Summary,
+			Responses: map[string]*openapiResponse{},
+		}
+		for _, p := range r.Doc.Parameters {
+			in := p.In
+			if in == "" {
+				in = "query"
+			}
+			typ := p.Type
+			if typ == "" {
+				typ = "string"
+			}
+			op.Parameters = append(op.Parameters, &openapiParameter{
+				Name:        p.Name,
+				In:          in,
+				Description: p.Description,
+				Required:    p.Required || in == "path",
+				Schema:      &openapiSchema{Type: typ},
+			})
+		}
+
+		contentType := r.Doc.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		var schema interface{}
+		if r.Doc.Response != nil {
+			schema = schemas.schemaOf(reflect.TypeOf(r.Doc.Response))
+		} else if r.Doc.Schema != nil {
+			schema = r.Doc.Schema
+		} else if contentType == "text/html" {
+			schema = &openapiSchema{Type: "string"}
+		} else {
+			schema = &openapiSchema{}
+		}
+		op.Responses["200"] = &openapiResponse{
+			Description: "OK",
+			Content:     map[string]openapiMedia{contentType: {Schema: schema}},
+		}
+		if isJSONPath(r.Path) {
+			for _, code := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError} {
+				op.Responses[strconv.Itoa(code)] = &openapiResponse{
+					Description: http.StatusText(code),
+					Content:     map[string]openapiMedia{"application/json": {Schema: errorSchema}},
+				}
+			}
+		}
+
+		path := openapiPathOf(r.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*openapiOperation{}
+		}
+		doc.Paths[path][strings.ToLower(r.Method)] = op
+	}
+	doc.Components.Schemas = schemas.components
+	return doc
+}
+
+// addAPIDoc keeps the description of the route added by AddURL
+func (e *BlockExplorer) addAPIDoc(method string, path string, doc *APIDoc) {
+	if method == "ANY" {
+		method = "GET"
+	}
+
+	e.apiDocsLock.Lock()
+	defer e.apiDocsLock.Unlock()
+
+	e.apiRoutes = append(e.apiRoutes, apiRoute{Method: method, Path: path, Doc: doc})
+	sort.SliceStable(e.apiRoutes, func(i, j int) bool {
+		return e.apiRoutes[i].Path < e.apiRoutes[j].Path
+	})
+}
+
+func (e *BlockExplorer) openapiHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, e.openapiDocument())
+}
@@ -0,0 +1,40 @@
+package blockexplorer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOpenAPIDocumentCoversRoutes(t *testing.T) {
+	e := &BlockExplorer{assets: NewFileAsset(Assets, "")}
+	e.initDataHandlers()
+	e.InitURL()
+	e.addAPIDoc("ANY", "/custom/:id", &APIDoc{
+		Summary:  "custom route",
+		Response: struct{ Value int }{},
+	})
+
+	doc := e.openapiDocument()
+	for _, path := range []string{"/", "/data/lastestBlocks.data", "/api/v1/blocks/{id}", "/custom/{id}"} {
+		if doc.Paths[path]["get"] == nil {
+			t.Fatalf("%s is not documented", path)
+		}
+	}
+
+	if doc.Paths["/data/lastestBlocks.data"]["get"].Responses["404"] == nil {
+		t.Fatal("the error responses of the data handlers are not documented")
+	}
+
+	s := doc.Components.Schemas["blockInfosCase"]
+	if s == nil {
+		t.Fatal("blockInfosCase is not in the components")
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := doc.Components.Schemas["chainInfoData"]
+	if chain == nil || chain.Properties["foumulators"] == nil {
+		t.Fatal("the embedded fields of chainInfoData are not promoted")
+	}
+}
@@ -0,0 +1,134 @@
+package blockexplorer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/fletaio/core/data"
+)
+
+// ErrReindexStalled is returned when the reindex can not index the next block
+var ErrReindexStalled = errors.New("Reindex is stalled, the next block can not be indexed")
+
+// ReindexProgress is the progress of the reindex reported after every chunk
+type ReindexProgress struct {
+	Height       uint32
+	Target       uint32
+	Transactions int
+	Elapsed      time.Duration
+	BlocksPerSec float64
+	TxsPerSec    float64
+}
+
+// Reindex rebuilds the explorer db of the path from the blocks of the source
+// the blocks are indexed into a fresh directory which replaces the db when every block is indexed,
+// so the current db is kept as it was if the reindex fails
+// the source is only read, the kernel store can be given without starting the kernel
+// workers is the number of the block fetching workers, the number of cpus is used when it is not positive
+func Reindex(dbPath string, source BlockSource, GenesisContextData *data.ContextData, workers int, progress func(p *ReindexProgress)) error {
+	if err := recoverReindex(dbPath); err != nil {
+		return err
+	}
+	tmpPath, oldPath := reindexPaths(dbPath)
+
+	e, err := openBlockExplorer(tmpPath, source, "", GenesisContextData)
+	if err != nil {
+		return err
+	}
+	e.SetIndexWorkers(workers)
+
+	target := source.Provider().Height()
+	begin := time.Now()
+	for e.indexedHeight < target {
+		from := e.indexedHeight
+		to := target
+		if to-from > indexChunkSize {
+			to = from + indexChunkSize
+		}
+		if err := e.indexChunk(to); err != nil {
+			e.Close()
+			return err
+		}
+		if e.indexedHeight == from {
+			e.Close()
+			return ErrReindexStalled
+		}
+
+		if progress != nil {
+			elapsed := time.Since(begin)
+			p := &ReindexProgress{
+				Height:       e.indexedHeight,
+				Target:       target,
+				Transactions: e.chainState.Transactions,
+				Elapsed:      elapsed,
+			}
+			if sec := elapsed.Seconds(); sec > 0 {
+				p.BlocksPerSec = float64(e.indexedHeight) / sec
+				p.TxsPerSec = float64(e.chainState.Transactions) / sec
+			}
+			progress(p)
+		}
+	}
+	// the number of the formulators is updated by the explorer when it is started
+	e.chainState.Blocks = target
+	if err := e.saveCursor(); err != nil {
+		e.Close()
+		return err
+	}
+	if err := e.Close(); err != nil {
+		return err
+	}
+
+	// the old db is moved aside before the new db takes its path,
+	// recoverReindex completes the swap when it is stopped between the renames
+	if err := os.RemoveAll(oldPath); err != nil {
+		return err
+	}
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := os.Rename(dbPath, oldPath); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmpPath, dbPath); err != nil {
+		if _, statErr := os.Stat(oldPath); statErr == nil {
+			if rerr := os.Rename(oldPath, dbPath); rerr != nil {
+				return fmt.Errorf("%v, and the old db is not restored from %s: %v", err, oldPath, rerr)
+			}
+		}
+		return err
+	}
+	return os.RemoveAll(oldPath)
+}
+
+func reindexPaths(dbPath string) (tmpPath string, oldPath string) {
+	return dbPath + ".reindex", dbPath + ".old"
+}
+
+// recoverReindex cleans up the directories of a reindex which is stopped before it is finished
+// the reindexed db is complete when the old db is moved aside, so the swap is completed by it,
+// otherwise the partial reindex is removed and the current db is kept
+func recoverReindex(dbPath string) error {
+	tmpPath, oldPath := reindexPaths(dbPath)
+	if !pathExists(dbPath) && pathExists(oldPath) {
+		from := oldPath
+		if pathExists(tmpPath) {
+			from = tmpPath
+		}
+		log.Println("explorer: recover the db of the stopped reindex from", from)
+		if err := os.Rename(from, dbPath); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return err
+	}
+	return os.RemoveAll(oldPath)
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,271 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+)
+
+// ErrRollbackTooDeep is returned when the fork point is older than the kept undo records
+var ErrRollbackTooDeep = errors.New("Rollback is deeper than the kept undo records")
+
+// undoDepth is the number of recent blocks that can be unwound
+const undoDepth = 1000
+
+type rollbackInfo struct {
+	From uint32 `json:"from"`
+	To   uint32 `json:"to"`
+	Time int64  `json:"time"`
+}
+
+type indexerStatus struct {
+	IndexedHeight uint32        `json:"indexedHeight"`
+	ChainHeight   uint32        `json:"chainHeight"`
+	Progress      float64       `json:"progress"`
+	Rollbacks     int           `json:"rollbacks"`
+	LastRollback  *rollbackInfo `json:"lastRollback"`
+}
+
+func (e *BlockExplorer) status() indexerStatus {
+	snap := e.snapshot()
+	s := indexerStatus{
+		IndexedHeight: snap.IndexedHeight,
+		ChainHeight:   e.Kernel.Provider().Height(),
+		Progress:      100,
+		Rollbacks:     snap.Rollbacks,
+		LastRollback:  snap.LastRollback,
+	}
+	if s.ChainHeight > 0 && s.IndexedHeight < s.ChainHeight {
+		s.Progress = float64(s.IndexedHeight) * 100 / float64(s.ChainHeight)
+	}
+	return s
+}
+
+// isIndexedBlock returns true when the block of the hash is indexed at the height
+func isIndexedBlock(txn *badger.Txn, height uint32, h hash.Hash256) (bool, error) {
+	item, err := txn.Get(heightHashKey(height))
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(value, h[:]), nil
+}
+
+// unindexOldBlock removes the entries of the block written by the old version which only kept
+// the hashes and the block count of the formulator, so the block is indexed again with every index
+func unindexOldBlock(txn *badger.Txn, b *block.Block, height uint32) error {
+	h := b.Header.Hash()
+	item, err := txn.Get(blockHashKey(h))
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		return err
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return err
+	}
+	if len(value) != 4 || util.BytesToUint32(value) != height {
+		return nil
+	}
+
+	if err := txn.Delete(blockHashKey(h)); err != nil {
+		return err
+	}
+	for _, tx := range b.Body.Transactions {
+		if err := txn.Delete(txHashKey(tx.Hash())); err != nil {
+			return err
+		}
+	}
+
+	key := formulatorKey(b.Header.Formulator)
+	item, err = txn.Get(key)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		return err
+	}
+	if value, err = item.ValueCopy(nil); err != nil {
+		return err
+	}
+	fr, err := readFormulatorRecord(value)
+	if err != nil {
+		return err
+	}
+	if fr.Blocks <= 1 {
+		return txn.Delete(key)
+	}
+	fr.Blocks--
+	buf := &bytes.Buffer{}
+	if _, err := fr.WriteTo(buf); err != nil {
+		return err
+	}
+	return txn.Set(key, buf.Bytes())
+}
+
+// commitUndo stores the indexed hash of the height and the undo of the block
+func commitUndo(it *indexTxn, height uint32, h hash.Hash256) error {
+	if err := it.Set(heightHashKey(height), h[:]); err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	if _, err := it.undo.WriteTo(buf); err != nil {
+		return err
+	}
+	if err := it.txn.Set(blockUndoKey(height), buf.Bytes()); err != nil {
+		return err
+	}
+	if height > undoDepth {
+		if err := it.txn.Delete(blockUndoKey(height - undoDepth)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *BlockExplorer) indexedHash(height uint32) (h hash.Hash256, has bool, err error) {
+	err = e.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(heightHashKey(height))
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return nil
+			}
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		copy(h[:], value)
+		has = true
+		return nil
+	})
+	return
+}
+
+func (e *BlockExplorer) isIndexedOnChain(height uint32) (bool, error) {
+	h, has, err := e.indexedHash(height)
+	if err != nil {
+		return false, err
+	}
+	if !has {
+		// indexed before the hash of the height is recorded
+		return true, nil
+	}
+	if height > e.source.Provider().Height() {
+		return false, nil
+	}
+	chainHash, err := e.source.Provider().Hash(height)
+	if err != nil {
+		return false, err
+	}
+	return h == chainHash, nil
+}
+
+// checkReorganization unwinds the indexed blocks which are not on the current chain anymore
+func (e *BlockExplorer) checkReorganization() error {
+	indexed := e.indexedHeight
+	if indexed == 0 {
+		return nil
+	}
+	if onChain, err := e.isIndexedOnChain(indexed); err != nil {
+		return err
+	} else if onChain {
+		return nil
+	}
+
+	// the walk stops at the undo depth because the blocks below it can not be unwound anyway
+	fork := indexed - 1
+	for {
+		if indexed-fork > undoDepth {
+			log.Println("explorer: chain reorganization below", fork+1, "is deeper than", undoDepth, "blocks, reindex is required")
+			return ErrRollbackTooDeep
+		}
+		if fork == 0 {
+			break
+		}
+		onChain, err := e.isIndexedOnChain(fork)
+		if err != nil {
+			return err
+		}
+		if onChain {
+			break
+		}
+		fork--
+	}
+
+	log.Println("explorer: chain reorganization detected, rollback from", indexed, "to", fork)
+	for height := indexed; height > fork; height-- {
+		if err := e.rollbackBlock(height); err != nil {
+			e.publish()
+			return err
+		}
+		e.indexedHeight = height - 1
+	}
+
+	// the peaks of the unwound blocks are restored by the undo
+	e.tps = newTpsMeter()
+	if err := e.db.View(e.loadTpsPeaks); err != nil {
+		e.publish()
+		return err
+	}
+	e.rebuildTps()
+	e.rebuildRecentFormulators()
+
+	e.rollbacks++
+	e.lastRollback = &rollbackInfo{
+		From: indexed,
+		To:   fork,
+		Time: time.Now().UnixNano(),
+	}
+	e.publish()
+	return e.saveCursor()
+}
+
+// rollbackBlock restores the index before the block and moves the cursor to the previous height in a db transaction
+func (e *BlockExplorer) rollbackBlock(height uint32) error {
+	var count int
+	if err := e.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(blockUndoKey(height))
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return ErrRollbackTooDeep
+			}
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		u := &indexUndo{}
+		if _, err := u.ReadFrom(bytes.NewReader(value)); err != nil {
+			return err
+		}
+		if err := restoreUndo(txn, u); err != nil {
+			return err
+		}
+		if err := txn.Delete(blockUndoKey(height)); err != nil {
+			return err
+		}
+		count, err = e.writeProgress(txn, height-1)
+		return err
+	}); err != nil {
+		return err
+	}
+	e.chainState.Transactions = count
+	return nil
+}
@@ -0,0 +1,187 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+)
+
+// rollup resolutions
+const (
+	rollupMinute byte = 1
+	rollupHour   byte = 2
+	rollupDay    byte = 3
+)
+
+var rollupResolutions = map[string]byte{
+	"minute": rollupMinute,
+	"hour":   rollupHour,
+	"day":    rollupDay,
+}
+
+var rollupIntervals = map[byte]time.Duration{
+	rollupMinute: time.Minute,
+	rollupHour:   time.Hour,
+	rollupDay:    24 * time.Hour,
+}
+
+// rollupMaxPoints is the maximum number of buckets in a response
+const rollupMaxPoints = 2000
+
+// rollupRecord is the number of blocks and transactions in a time bucket
+type rollupRecord struct {
+	Blocks uint32
+	Txs    uint64
+}
+
+// WriteTo is a serialization function
+func (rr *rollupRecord) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint32(w, rr.Blocks); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint64(w, rr.Txs); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (rr *rollupRecord) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		rr.Blocks = v
+	}
+	if v, n, err := util.ReadUint64(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		rr.Txs = v
+	}
+	return read, nil
+}
+
+// rollupBucket returns the start of the bucket of the timestamp in unix seconds
+func rollupBucket(res byte, timestamp uint64) uint64 {
+	interval := uint64(rollupIntervals[res] / time.Second)
+	sec := timestamp / uint64(time.Second)
+	return sec - sec%interval
+}
+
+// updateRollups adds the block to the minute, hour and day buckets of its timestamp
+func (e *BlockExplorer) updateRollups(it *indexTxn, b *block.Block) error {
+	for _, res := range []byte{rollupMinute, rollupHour, rollupDay} {
+		key := rollupKey(res, rollupBucket(res, b.Header.Timestamp()))
+		rr := &rollupRecord{}
+		value, err := it.Get(key)
+		if err != nil {
+			return err
+		}
+		if value != nil {
+			if _, err := rr.ReadFrom(bytes.NewReader(value)); err != nil {
+				return err
+			}
+		}
+		rr.Blocks++
+		rr.Txs += uint64(len(b.Body.Transactions))
+
+		buf := &bytes.Buffer{}
+		if _, err := rr.WriteTo(buf); err != nil {
+			return err
+		}
+		if err := it.Set(key, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRollupRange parses the duration of the range, the day suffix (7d) is allowed
+func parseRollupRange(rangeStr string) (time.Duration, error) {
+	if strings.HasSuffix(rangeStr, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(rangeStr, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(rangeStr)
+}
+
+// rollups returns the counts of every bucket in the range to the current time, the empty buckets are zero
+func (e *BlockExplorer) rollups(res byte, rng time.Duration, blocks bool) []*countInfo {
+	interval := uint64(rollupIntervals[res] / time.Second)
+	end := rollupBucket(res, uint64(time.Now().UnixNano()))
+	points := uint64(rng/time.Second) / interval
+	if points == 0 {
+		points = 1
+	}
+	if points > rollupMaxPoints {
+		points = rollupMaxPoints
+	}
+	start := end - (points-1)*interval
+
+	counts := map[uint64]int{}
+	e.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		prefix := []byte{rollupPrefix, res}
+		for it.Seek(rollupKey(res, start)); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			bucket := keyToUint64(item.Key()[len(prefix):])
+			if bucket > end {
+				break
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			rr := &rollupRecord{}
+			if _, err := rr.ReadFrom(bytes.NewReader(value)); err != nil {
+				continue
+			}
+			if blocks {
+				counts[bucket] = int(rr.Blocks)
+			} else {
+				counts[bucket] = int(rr.Txs)
+			}
+		}
+		return nil
+	})
+
+	list := make([]*countInfo, 0, points)
+	for bucket := start; bucket <= end; bucket += interval {
+		list = append(list, &countInfo{
+			Time:  int64(bucket) * int64(time.Second),
+			Count: counts[bucket],
+		})
+	}
+	return list
+}
+
+// transactions returns the throughput chart of the range and the resolution, the recent hour by minutes as default
+func (e *BlockExplorer) transactions(rangeStr string, resStr string, metric string) []*countInfo {
+	res, has := rollupResolutions[resStr]
+	if !has {
+		res = rollupMinute
+	}
+	rng, err := parseRollupRange(rangeStr)
+	if err != nil || rng <= 0 {
+		rng = time.Hour
+	}
+	return e.rollups(res, rng, metric == "blocks")
+}
@@ -0,0 +1,183 @@
+package blockexplorer
+
+import (
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+)
+
+// searchSuggestionLimit is the maximum number of the hash prefix suggestions
+const searchSuggestionLimit = 10
+
+// searchMinPrefix is the minimum length of the hex prefix to suggest the hashes
+const searchMinPrefix = 4
+
+// search result types
+const (
+	searchHeight      = "height"
+	searchBlock       = "block"
+	searchTransaction = "transaction"
+	searchAddress     = "address"
+	searchName        = "name"
+)
+
+type searchMatch struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	URL   string `json:"url"`
+}
+
+type searchResult struct {
+	Query       string        `json:"query"`
+	Matches     []searchMatch `json:"matches"`
+	Suggestions []searchMatch `json:"suggestions"`
+}
+
+func blockHeightURL(height uint32) string {
+	return "/blockDetail?height=" + strconv.FormatUint(uint64(height), 10)
+}
+
+func blockHashURL(h string) string {
+	return "/blockDetail?hash=" + h
+}
+
+func txHashURL(h string) string {
+	return "/transactionDetail?hash=" + h
+}
+
+func accountURL(addr common.Address) string {
+	return "/account?addr=" + url.QueryEscape(addr.String())
+}
+
+// search classifies the query as a height, a block hash, a transaction hash, an address or an account name
+// and returns every candidate, the indexed hashes starting with the query are suggested when the query is a hex prefix
+func (e *BlockExplorer) search(q string) *searchResult {
+	q = strings.TrimSpace(q)
+	result := &searchResult{
+		Query:       q,
+		Matches:     []searchMatch{},
+		Suggestions: []searchMatch{},
+	}
+	if q == "" {
+		return result
+	}
+
+	if v, err := strconv.ParseUint(q, 10, 32); err == nil {
+		if height := uint32(v); height <= e.Kernel.Provider().Height() {
+			result.Matches = append(result.Matches, searchMatch{
+				Type:  searchHeight,
+				Value: q,
+				URL:   blockHeightURL(height),
+			})
+		}
+	}
+
+	if h, err := hash.ParseHex(q); err == nil {
+		e.db.View(func(txn *badger.Txn) error {
+			if _, err := txn.Get(blockHashKey(h)); err == nil {
+				result.Matches = append(result.Matches, searchMatch{
+					Type:  searchBlock,
+					Value: h.String(),
+					URL:   blockHashURL(h.String()),
+				})
+			}
+			if _, err := txn.Get(txHashKey(h)); err == nil {
+				result.Matches = append(result.Matches, searchMatch{
+					Type:  searchTransaction,
+					Value: h.String(),
+					URL:   txHashURL(h.String()),
+				})
+			}
+			return nil
+		})
+	}
+
+	if addr, err := common.ParseAddress(q); err == nil {
+		result.Matches = append(result.Matches, searchMatch{
+			Type:  searchAddress,
+			Value: addr.String(),
+			URL:   accountURL(addr),
+		})
+	}
+
+	if addr, has := e.addressByName(q); has {
+		result.Matches = append(result.Matches, searchMatch{
+			Type:  searchName,
+			Value: q,
+			URL:   accountURL(addr),
+		})
+	}
+
+	if len(result.Matches) == 0 {
+		result.Suggestions = e.hashSuggestions(strings.ToLower(q))
+	}
+	return result
+}
+
+// addressByName returns the address of the account name from the name index or from the loader of the kernel
+func (e *BlockExplorer) addressByName(name string) (common.Address, bool) {
+	if addr, has := e.registeredAddress(name); has {
+		return addr, true
+	}
+	if l, ok := e.Kernel.Loader().(interface {
+		AddressByName(name string) (common.Address, error)
+	}); ok {
+		if addr, err := l.AddressByName(name); err == nil {
+			return addr, true
+		}
+	}
+	return common.Address{}, false
+}
+
+// hashSuggestions returns the block and transaction hashes starting with the hex prefix
+func (e *BlockExplorer) hashSuggestions(prefix string) []searchMatch {
+	suggestions := []searchMatch{}
+	if len(prefix) < searchMinPrefix || len(prefix) >= hashSize*2 {
+		return suggestions
+	}
+	// the odd last digit is compared by the string of the hash
+	bs, err := hex.DecodeString(prefix[:len(prefix)/2*2])
+	if err != nil {
+		return suggestions
+	}
+	if len(prefix)%2 == 1 {
+		if _, err := strconv.ParseUint(prefix[len(prefix)-1:], 16, 8); err != nil {
+			return suggestions
+		}
+	}
+
+	e.db.View(func(txn *badger.Txn) error {
+		for _, s := range []struct {
+			prefix byte
+			Type   string
+			URL    func(h string) string
+		}{
+			{blockHashPrefix, searchBlock, blockHashURL},
+			{txHashPrefix, searchTransaction, txHashURL},
+		} {
+			keyPrefix := append([]byte{s.prefix}, bs...)
+			opts := badger.DefaultIteratorOptions
+			opts.PrefetchValues = false
+			it := txn.NewIterator(opts)
+			for it.Seek(keyPrefix); it.ValidForPrefix(keyPrefix) && len(suggestions) < searchSuggestionLimit; it.Next() {
+				h := hex.EncodeToString(it.Item().Key()[1:])
+				if !strings.HasPrefix(h, prefix) {
+					continue
+				}
+				suggestions = append(suggestions, searchMatch{
+					Type:  s.Type,
+					Value: h,
+					URL:   s.URL(h),
+				})
+			}
+			it.Close()
+		}
+		return nil
+	})
+	return suggestions
+}
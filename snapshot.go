@@ -0,0 +1,48 @@
+package blockexplorer
+
+import "github.com/fletaio/common"
+
+// explorerSnapshot is a consistent view of the indexer state
+// the indexer builds a new snapshot and publishes it, a published snapshot is never modified
+// so the handlers read it without any lock
+type explorerSnapshot struct {
+	ChainInfo     currentChainInfo
+	IndexedHeight uint32
+	Rollbacks     int
+	LastRollback  *rollbackInfo
+	Tps           map[string]float64
+	PeakTps       map[string]tpsPeak
+
+	RecentFormulators map[common.Address]int
+	RecentBlocks      int
+}
+
+// publish replaces the snapshot by the current state of the indexer
+// it must be called by the goroutine which owns the indexer state
+func (e *BlockExplorer) publish() {
+	s := &explorerSnapshot{
+		ChainInfo:     e.chainState,
+		IndexedHeight: e.indexedHeight,
+		Rollbacks:     e.rollbacks,
+		Tps:           e.tps.Current(),
+		PeakTps:       e.tps.Peaks(),
+	}
+	s.RecentFormulators, s.RecentBlocks = e.recents.Counts()
+	if e.lastRollback != nil {
+		r := *e.lastRollback
+		s.LastRollback = &r
+	}
+	e.state.Store(s)
+}
+
+// snapshot returns the last published snapshot
+func (e *BlockExplorer) snapshot() *explorerSnapshot {
+	if s, ok := e.state.Load().(*explorerSnapshot); ok {
+		return s
+	}
+	return &explorerSnapshot{
+		Tps:               map[string]float64{},
+		PeakTps:           map[string]tpsPeak{},
+		RecentFormulators: map[common.Address]int{},
+	}
+}
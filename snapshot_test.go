@@ -0,0 +1,80 @@
+package blockexplorer
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshotConsistentUnderIndexing(t *testing.T) {
+	e := &BlockExplorer{
+		tps: newTpsMeter(),
+	}
+	e.publish()
+
+	const blocks = 2000
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		base := uint64(time.Now().UnixNano())
+		for height := uint32(1); height <= blocks; height++ {
+			e.indexedHeight = height
+			e.chainState.Blocks = height
+			e.chainState.Transactions += 3
+			e.tps.Add(height, base+uint64(height)*uint64(time.Second), 3)
+			if height%100 == 0 {
+				e.rollbacks++
+				e.lastRollback = &rollbackInfo{From: height, To: height - 1}
+			}
+			e.publish()
+		}
+	}()
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var last uint32
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				s := e.snapshot()
+				if s.IndexedHeight < last {
+					t.Errorf("indexed height goes back from %d to %d", last, s.IndexedHeight)
+					return
+				}
+				last = s.IndexedHeight
+				if s.ChainInfo.Blocks != s.IndexedHeight || s.ChainInfo.Transactions != int(s.IndexedHeight)*3 {
+					t.Errorf("inconsistent snapshot %+v", s)
+					return
+				}
+				if s.Rollbacks != int(s.IndexedHeight/100) {
+					t.Errorf("rollbacks %d at the height %d", s.Rollbacks, s.IndexedHeight)
+					return
+				}
+				if s.LastRollback != nil && s.LastRollback.From != s.IndexedHeight/100*100 {
+					t.Errorf("last rollback %+v at the height %d", s.LastRollback, s.IndexedHeight)
+					return
+				}
+				info := e.chainInfo()
+				for name := range info.Tps {
+					if info.Tps[name] < 0 || info.PeakTps[name].Tps < info.Tps[name] {
+						t.Errorf("tps %v is over the peak %v", info.Tps[name], info.PeakTps[name])
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s := e.snapshot(); s.IndexedHeight != blocks {
+		t.Errorf("indexed height is %d, expected %d", s.IndexedHeight, blocks)
+	}
+}
@@ -0,0 +1,359 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+)
+
+// tpsWindows are the sliding windows of the tps measurement
+var tpsWindows = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{"10s", 10 * time.Second},
+	{"1m", time.Minute},
+	{"1h", time.Hour},
+}
+
+// tpsRebuildLimit is the maximum number of blocks loaded to rebuild the windows at the start
+const tpsRebuildLimit = 20000
+
+type tpsSample struct {
+	height    uint32
+	timestamp uint64
+	txs       int
+}
+
+// tpsPeak is the highest tps of a window and the blocks of it
+type tpsPeak struct {
+	Tps        float64 `json:"tps"`
+	FromHeight uint32  `json:"fromHeight"`
+	ToHeight   uint32  `json:"toHeight"`
+	Time       uint64  `json:"time"`
+}
+
+type tpsWindow struct {
+	duration time.Duration
+	start    int
+	txs      int
+	peak     *tpsPeak
+}
+
+// tpsMeter measures the tps of the sliding windows by the timestamps of the indexed blocks
+type tpsMeter struct {
+	sync.Mutex
+	windows map[string]*tpsWindow
+	samples []tpsSample
+}
+
+func newTpsMeter() *tpsMeter {
+	m := &tpsMeter{
+		windows: map[string]*tpsWindow{},
+		samples: []tpsSample{},
+	}
+	for _, tw := range tpsWindows {
+		m.windows[tw.Name] = &tpsWindow{
+			duration: tw.Duration,
+			peak:     &tpsPeak{},
+		}
+	}
+	return m
+}
+
+// Clone returns a copy of the meter
+func (m *tpsMeter) Clone() *tpsMeter {
+	m.Lock()
+	defer m.Unlock()
+
+	c := &tpsMeter{
+		windows: map[string]*tpsWindow{},
+		samples: append([]tpsSample{}, m.samples...),
+	}
+	for name, w := range m.windows {
+		cw := *w
+		c.windows[name] = &cw
+	}
+	return c
+}
+
+// Add puts the block into the windows and updates the peaks, it returns true when a peak is raised
+func (m *tpsMeter) Add(height uint32, timestamp uint64, txs int) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	raised := false
+
+	m.samples = append(m.samples, tpsSample{
+		height:    height,
+		timestamp: timestamp,
+		txs:       txs,
+	})
+	last := len(m.samples) - 1
+	oldest := last
+	for _, w := range m.windows {
+		w.txs += txs
+		for w.start < last && m.samples[w.start].timestamp <= timestamp && timestamp-m.samples[w.start].timestamp >= uint64(w.duration) {
+			w.txs -= m.samples[w.start].txs
+			w.start++
+		}
+		if tps := float64(w.txs) / w.duration.Seconds(); tps > w.peak.Tps {
+			w.peak = &tpsPeak{
+				Tps:        tps,
+				FromHeight: m.samples[w.start].height,
+				ToHeight:   height,
+				Time:       timestamp,
+			}
+			raised = true
+		}
+		if w.start < oldest {
+			oldest = w.start
+		}
+	}
+
+	// drops the samples out of every window
+	if oldest > len(m.samples)/2 {
+		m.samples = append([]tpsSample{}, m.samples[oldest:]...)
+		for _, w := range m.windows {
+			w.start -= oldest
+		}
+	}
+	return raised
+}
+
+// Reset clears the samples of the windows but keeps the peaks
+func (m *tpsMeter) Reset() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.samples = []tpsSample{}
+	for _, w := range m.windows {
+		w.start = 0
+		w.txs = 0
+	}
+}
+
+// Current returns the tps of the windows ending at the last block
+func (m *tpsMeter) Current() map[string]float64 {
+	m.Lock()
+	defer m.Unlock()
+
+	tps := map[string]float64{}
+	for name, w := range m.windows {
+		tps[name] = float64(w.txs) / w.duration.Seconds()
+	}
+	return tps
+}
+
+// Peaks returns the peaks of the windows
+func (m *tpsMeter) Peaks() map[string]tpsPeak {
+	m.Lock()
+	defer m.Unlock()
+
+	peaks := map[string]tpsPeak{}
+	for name, w := range m.windows {
+		peaks[name] = *w.peak
+	}
+	return peaks
+}
+
+// WriteTo is a serialization function of the peaks
+func (m *tpsMeter) WriteTo(w io.Writer) (int64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var wrote int64
+	if n, err := util.WriteUint32(w, uint32(len(tpsWindows))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, tw := range tpsWindows {
+		p := m.windows[tw.Name].peak
+		if n, err := writeBytes(w, []byte(tw.Name)); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := util.WriteUint64(w, math.Float64bits(p.Tps)); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := util.WriteUint32(w, p.FromHeight); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := util.WriteUint32(w, p.ToHeight); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := util.WriteUint64(w, p.Time); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function of the peaks
+func (m *tpsMeter) ReadFrom(r io.Reader) (int64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var read int64
+	Len, n, err := util.ReadUint32(r)
+	if err != nil {
+		return read, err
+	}
+	read += n
+	for i := uint32(0); i < Len; i++ {
+		name, n, err := readBytes(r)
+		if err != nil {
+			return read, err
+		}
+		read += n
+		p := &tpsPeak{}
+		if v, n, err := util.ReadUint64(r); err != nil {
+			return read, err
+		} else {
+			read += n
+			p.Tps = math.Float64frombits(v)
+		}
+		if v, n, err := util.ReadUint32(r); err != nil {
+			return read, err
+		} else {
+			read += n
+			p.FromHeight = v
+		}
+		if v, n, err := util.ReadUint32(r); err != nil {
+			return read, err
+		} else {
+			read += n
+			p.ToHeight = v
+		}
+		if v, n, err := util.ReadUint64(r); err != nil {
+			return read, err
+		} else {
+			read += n
+			p.Time = v
+		}
+		if w, has := m.windows[string(name)]; has {
+			w.peak = p
+		}
+	}
+	return read, nil
+}
+
+// rebuildTps loads the indexed blocks in the longest window to measure the current tps after a restart or a rollback
+func (e *BlockExplorer) rebuildTps() {
+	e.tps.Reset()
+
+	longest := tpsWindows[len(tpsWindows)-1].Duration
+	samples := []tpsSample{}
+	e.db.View(func(txn *badger.Txn) error {
+		var last uint64
+		for height := e.indexedHeight; height > 0 && len(samples) < tpsRebuildLimit; height-- {
+			bt, err := e.blockTypesByHeight(txn, height)
+			if err != nil {
+				break
+			}
+			if last == 0 {
+				last = bt.Timestamp
+			} else if bt.Timestamp <= last && last-bt.Timestamp >= uint64(longest) {
+				break
+			}
+			txs := 0
+			for _, c := range bt.Types {
+				txs += c
+			}
+			samples = append(samples, tpsSample{
+				height:    height,
+				timestamp: bt.Timestamp,
+				txs:       txs,
+			})
+		}
+		return nil
+	})
+
+	for i := len(samples) - 1; i >= 0; i-- {
+		e.tps.Add(samples[i].height, samples[i].timestamp, samples[i].txs)
+	}
+}
+
+// updateTpsPeaks adds the block to the meter and stores the peaks when the block raises one,
+// so the undo of the block keeps the previous peaks for a rollback
+func updateTpsPeaks(it *indexTxn, tps *tpsMeter, b *block.Block, height uint32) error {
+	if !tps.Add(height, b.Header.Timestamp(), len(b.Body.Transactions)) {
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	if _, err := tps.WriteTo(buf); err != nil {
+		return err
+	}
+	return it.Set(tpsPeaksKey, buf.Bytes())
+}
+
+func init() {
+	registerMigration(3, "timestamp based tps", migrateMaximumTps)
+}
+
+// migrateMaximumTps converts the maximum tps of the old version into the peak of the shortest window
+// the old maximum is the number of the transactions in two sequential blocks,
+// so the shortest window which contains them has at least the tps of the maximum over the window
+func migrateMaximumTps(db *badger.DB) error {
+	return db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(maximumTpsKey)
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return nil
+			}
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		if len(value) == 4 {
+			shortest := tpsWindows[0]
+			m := newTpsMeter()
+			m.windows[shortest.Name].peak = &tpsPeak{
+				Tps: float64(util.BytesToUint32(value)) / shortest.Duration.Seconds(),
+			}
+			buf := &bytes.Buffer{}
+			if _, err := m.WriteTo(buf); err != nil {
+				return err
+			}
+			if err := txn.Set(tpsPeaksKey, buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return txn.Delete(maximumTpsKey)
+	})
+}
+
+// loadTpsPeaks reads the stored peaks
+func (e *BlockExplorer) loadTpsPeaks(txn *badger.Txn) error {
+	item, err := txn.Get(tpsPeaksKey)
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		return err
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return err
+	}
+	_, err = e.tps.ReadFrom(bytes.NewReader(value))
+	return err
+}
@@ -0,0 +1,112 @@
+package blockexplorer
+
+import (
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+)
+
+type txPosition struct {
+	height uint32
+	index  uint32
+}
+
+func getTxCount(txn *badger.Txn) (uint64, error) {
+	item, err := txn.Get(txCountKey)
+	if err != nil {
+		if err != badger.ErrKeyNotFound {
+			return 0, err
+		}
+		return 0, nil
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return 0, err
+	}
+	return util.BytesToUint64(value), nil
+}
+
+// updateTxSequence appends the transactions of the block to the global transaction sequence
+func (e *BlockExplorer) updateTxSequence(it *indexTxn, b *block.Block, height uint32) error {
+	count, err := getTxCount(it.txn)
+	if err != nil {
+		return err
+	}
+	for i := range b.Body.Transactions {
+		v := append(util.Uint32ToBytes(height), util.Uint32ToBytes(uint32(i))...)
+		if err := it.Set(txSequenceKey(count), v); err != nil {
+			return err
+		}
+		count++
+	}
+	return it.Set(txCountKey, util.Uint64ToBytes(count))
+}
+
+// TransactionCount returns the number of the indexed transactions
+func (e *BlockExplorer) TransactionCount() (count uint64) {
+	e.db.View(func(txn *badger.Txn) error {
+		var err error
+		count, err = getTxCount(txn)
+		return err
+	})
+	return
+}
+
+// txSequenceList returns the positions of the transactions from the newest one skipping start
+func (e *BlockExplorer) txSequenceList(start int, length int) []txPosition {
+	positions := []txPosition{}
+	e.db.View(func(txn *badger.Txn) error {
+		count, err := getTxCount(txn)
+		if err != nil {
+			return err
+		}
+		if start < 0 || uint64(start) >= count {
+			return nil
+		}
+		for seq := int64(count) - 1 - int64(start); seq >= 0 && len(positions) < length; seq-- {
+			item, err := txn.Get(txSequenceKey(uint64(seq)))
+			if err != nil {
+				return err
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			positions = append(positions, txPosition{
+				height: util.BytesToUint32(value[0:4]),
+				index:  util.BytesToUint32(value[4:8]),
+			})
+		}
+		return nil
+	})
+	return positions
+}
+
+// txInfosByPosition loads the transactions from the kernel, each tx is labelled with the hash of its own block
+func (e *BlockExplorer) txInfosByPosition(positions []txPosition) []txInfos {
+	aaData := []txInfos{}
+	var b *block.Block
+	for _, p := range positions {
+		if b == nil || b.Header.Height() != p.height {
+			var err error
+			b, err = e.Kernel.Block(p.height)
+			if err != nil {
+				b = nil
+				continue
+			}
+		}
+		if int(p.index) >= len(b.Body.Transactions) {
+			continue
+		}
+		tx := b.Body.Transactions[p.index]
+		name, _ := e.Kernel.Transactor().NameByType(tx.Type())
+		aaData = append(aaData, txInfos{
+			TxHash:    tx.Hash().String(),
+			BlockHash: b.Header.Hash().String(),
+			ChainID:   b.Header.ChainCoord.String(),
+			Time:      tx.Timestamp(),
+			TxType:    name,
+		})
+	}
+	return aaData
+}
@@ -0,0 +1,209 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/block"
+)
+
+// typesPerBlockLimit is the maximum number of blocks in a typesPerBlock response
+const typesPerBlockLimit = 200
+
+// typesPerBlockDefault is the number of recent blocks when the range is not given
+const typesPerBlockDefault = 20
+
+// blockTypes is the transaction counts by the type name of a block
+type blockTypes struct {
+	Timestamp uint64
+	Types     map[string]int
+}
+
+// WriteTo is a serialization function
+func (bt *blockTypes) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint64(w, bt.Timestamp); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, uint32(len(bt.Types))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, name := range sortedTypeNames(bt.Types) {
+		if n, err := writeBytes(w, []byte(name)); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := util.WriteUint32(w, uint32(bt.Types[name])); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (bt *blockTypes) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if v, n, err := util.ReadUint64(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		bt.Timestamp = v
+	}
+	Len, n, err := util.ReadUint32(r)
+	if err != nil {
+		return read, err
+	}
+	read += n
+	bt.Types = map[string]int{}
+	for i := uint32(0); i < Len; i++ {
+		bs, n, err := readBytes(r)
+		if err != nil {
+			return read, err
+		}
+		read += n
+		v, n, err := util.ReadUint32(r)
+		if err != nil {
+			return read, err
+		}
+		read += n
+		bt.Types[string(bs)] = int(v)
+	}
+	return read, nil
+}
+
+func sortedTypeNames(types map[string]int) []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (e *BlockExplorer) blockTypesOf(b *block.Block) *blockTypes {
+	bt := &blockTypes{
+		Timestamp: b.Header.Timestamp(),
+		Types:     map[string]int{},
+	}
+	for _, tx := range b.Body.Transactions {
+		name, err := e.source.Transactor().NameByType(tx.Type())
+		if err != nil {
+			name = "unknown"
+		}
+		bt.Types[name]++
+	}
+	return bt
+}
+
+// updateBlockTypes stores the transaction counts by the type name of the block
+func (e *BlockExplorer) updateBlockTypes(it *indexTxn, b *block.Block, height uint32) error {
+	buf := &bytes.Buffer{}
+	if _, err := e.blockTypesOf(b).WriteTo(buf); err != nil {
+		return err
+	}
+	return it.Set(blockTypesKey(height), buf.Bytes())
+}
+
+// blockTypesByHeight returns the indexed counts of the height, the block is counted again if it is not indexed yet
+func (e *BlockExplorer) blockTypesByHeight(txn *badger.Txn, height uint32) (*blockTypes, error) {
+	item, err := txn.Get(blockTypesKey(height))
+	if err != nil {
+		if err != badger.ErrKeyNotFound {
+			return nil, err
+		}
+		b, err := e.source.Block(height)
+		if err != nil {
+			return nil, err
+		}
+		return e.blockTypesOf(b), nil
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, err
+	}
+	bt := &blockTypes{}
+	if _, err := bt.ReadFrom(bytes.NewReader(value)); err != nil {
+		return nil, err
+	}
+	return bt, nil
+}
+
+// typesPerBlock returns a row per the block and the type name in the range,
+// every type in the range has a row of every block to draw the chart
+func (e *BlockExplorer) typesPerBlock(fromStr string, toStr string) ([]typePerBlock, error) {
+	result := []typePerBlock{}
+
+	top := e.snapshot().IndexedHeight
+	to := top
+	if toStr != "" {
+		v, err := strconv.ParseUint(toStr, 10, 32)
+		if err != nil {
+			return nil, ErrInvalidHeightFormat
+		}
+		if uint32(v) < to {
+			to = uint32(v)
+		}
+	}
+	var from uint32 = 1
+	if to > typesPerBlockDefault {
+		from = to - typesPerBlockDefault + 1
+	}
+	if fromStr != "" {
+		v, err := strconv.ParseUint(fromStr, 10, 32)
+		if err != nil {
+			return nil, ErrInvalidHeightFormat
+		}
+		from = uint32(v)
+	}
+	if from == 0 {
+		from = 1
+	}
+	if from > to {
+		return result, nil
+	}
+	if to-from >= typesPerBlockLimit {
+		if fromStr != "" {
+			to = from + typesPerBlockLimit - 1
+		} else {
+			from = to - typesPerBlockLimit + 1
+		}
+	}
+
+	bts := []*blockTypes{}
+	names := map[string]int{}
+	e.db.View(func(txn *badger.Txn) error {
+		for height := from; height <= to; height++ {
+			bt, err := e.blockTypesByHeight(txn, height)
+			if err != nil {
+				continue
+			}
+			bts = append(bts, bt)
+			for name := range bt.Types {
+				names[name] = 0
+			}
+		}
+		return nil
+	})
+
+	for _, name := range sortedTypeNames(names) {
+		for _, bt := range bts {
+			result = append(result, typePerBlock{
+				BlockTime: bt.Timestamp / 1000000,
+				Symbol:    name,
+				TxCount:   strconv.Itoa(bt.Types[name]),
+			})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,238 @@
+package blockexplorer
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/dgraph-io/badger"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/transaction"
+)
+
+var (
+	txInsType  = reflect.TypeOf([]*transaction.TxIn{})
+	txOutsType = reflect.TypeOf([]*transaction.TxOut{})
+)
+
+// utxoRecord is the transaction which created the output and the transaction which spent it
+type utxoRecord struct {
+	CreatedBy hash.Hash256
+	Spent     bool
+	SpentBy   hash.Hash256
+}
+
+// WriteTo is a serialization function
+func (ur *utxoRecord) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := w.Write(ur.CreatedBy[:]); err != nil {
+		return wrote, err
+	} else {
+		wrote += int64(n)
+	}
+	if !ur.Spent {
+		if n, err := w.Write([]byte{0}); err != nil {
+			return wrote, err
+		} else {
+			wrote += int64(n)
+		}
+		return wrote, nil
+	}
+	if n, err := w.Write([]byte{1}); err != nil {
+		return wrote, err
+	} else {
+		wrote += int64(n)
+	}
+	if n, err := w.Write(ur.SpentBy[:]); err != nil {
+		return wrote, err
+	} else {
+		wrote += int64(n)
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (ur *utxoRecord) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := io.ReadFull(r, ur.CreatedBy[:]); err != nil {
+		return read, err
+	} else {
+		read += int64(n)
+	}
+	spent := []byte{0}
+	if n, err := io.ReadFull(r, spent); err != nil {
+		return read, err
+	} else {
+		read += int64(n)
+	}
+	if spent[0] == 1 {
+		ur.Spent = true
+		if n, err := io.ReadFull(r, ur.SpentBy[:]); err != nil {
+			return read, err
+		} else {
+			read += int64(n)
+		}
+	}
+	return read, nil
+}
+
+// txUTXO returns the inputs and the outputs of the transaction
+func txUTXO(tx transaction.Transaction) (vin []*transaction.TxIn, vout []*transaction.TxOut) {
+	walkFields(reflect.ValueOf(tx), func(f reflect.StructField, fv reflect.Value) bool {
+		switch f.Type {
+		case txInsType:
+			vin = append(vin, fv.Interface().([]*transaction.TxIn)...)
+		case txOutsType:
+			vout = append(vout, fv.Interface().([]*transaction.TxOut)...)
+		}
+		return true
+	})
+	return
+}
+
+// updateUTXOs records the outputs created by the transactions of the block and marks the outputs spent by them
+func (e *BlockExplorer) updateUTXOs(it *indexTxn, b *block.Block, height uint32) error {
+	for i, tx := range b.Body.Transactions {
+		vin, vout := txUTXO(tx)
+		if len(vin) == 0 && len(vout) == 0 {
+			continue
+		}
+		h := tx.Hash()
+		for _, in := range vin {
+			if in == nil {
+				continue
+			}
+			key := utxoKey(in.Height, in.Index, in.N)
+			ur := &utxoRecord{}
+			value, err := it.Get(key)
+			if err != nil {
+				return err
+			}
+			if value != nil {
+				if _, err := ur.ReadFrom(bytes.NewReader(value)); err != nil {
+					return err
+				}
+			}
+			ur.Spent = true
+			ur.SpentBy = h
+			if err := setUTXORecord(it, key, ur); err != nil {
+				return err
+			}
+		}
+		for n := range vout {
+			ur := &utxoRecord{CreatedBy: h}
+			if err := setUTXORecord(it, utxoKey(height, uint16(i), uint16(n)), ur); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setUTXORecord(it *indexTxn, key []byte, ur *utxoRecord) error {
+	buf := &bytes.Buffer{}
+	if _, err := ur.WriteTo(buf); err != nil {
+		return err
+	}
+	return it.Set(key, buf.Bytes())
+}
+
+func (e *BlockExplorer) utxoRecordOf(txn *badger.Txn, height uint32, index uint16, n uint16) (*utxoRecord, error) {
+	item, err := txn.Get(utxoKey(height, index, n))
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, err
+	}
+	ur := &utxoRecord{}
+	if _, err := ur.ReadFrom(bytes.NewReader(value)); err != nil {
+		return nil, err
+	}
+	return ur, nil
+}
+
+type utxoInput struct {
+	ID     string `json:"ID"`
+	Height uint32 `json:"Height"`
+	Index  uint16 `json:"Index"`
+	N      uint16 `json:"N"`
+	TxHash string `json:"TxHash"`
+}
+
+type utxoOutput struct {
+	ID         string `json:"ID"`
+	N          int    `json:"N"`
+	Amount     string `json:"Amount"`
+	PublicHash string `json:"PublicHash"`
+	Spent      bool   `json:"Spent"`
+	SpentBy    string `json:"SpentBy"`
+}
+
+type utxoDetail struct {
+	Inputs  []utxoInput  `json:"Inputs"`
+	Outputs []utxoOutput `json:"Outputs"`
+}
+
+func utxoID(height uint32, index uint16, n uint16) string {
+	return strconv.FormatUint(uint64(height), 10) + "." + strconv.Itoa(int(index)) + "." + strconv.Itoa(int(n))
+}
+
+// utxoDetailOf returns the source transactions of the inputs and the spent state of the outputs of the transaction
+// it returns nil when the transaction is not a utxo transaction
+func (e *BlockExplorer) utxoDetailOf(tx transaction.Transaction, height uint32, index uint16) *utxoDetail {
+	vin, vout := txUTXO(tx)
+	if len(vin) == 0 && len(vout) == 0 {
+		return nil
+	}
+
+	d := &utxoDetail{
+		Inputs:  []utxoInput{},
+		Outputs: []utxoOutput{},
+	}
+	e.db.View(func(txn *badger.Txn) error {
+		for _, in := range vin {
+			if in == nil {
+				continue
+			}
+			ui := utxoInput{
+				ID:     utxoID(in.Height, in.Index, in.N),
+				Height: in.Height,
+				Index:  in.Index,
+				N:      in.N,
+			}
+			if ur, err := e.utxoRecordOf(txn, in.Height, in.Index, in.N); err == nil && ur != nil && ur.CreatedBy != (hash.Hash256{}) {
+				ui.TxHash = ur.CreatedBy.String()
+			} else if b, err := e.Kernel.Block(in.Height); err == nil && int(in.Index) < len(b.Body.Transactions) {
+				// the output is created before the utxo index or by the genesis, so only its spend is recorded
+				ui.TxHash = b.Body.Transactions[in.Index].Hash().String()
+			}
+			d.Inputs = append(d.Inputs, ui)
+		}
+		for n, out := range vout {
+			uo := utxoOutput{
+				ID: utxoID(height, index, uint16(n)),
+				N:  n,
+			}
+			if out != nil {
+				if out.Amount != nil {
+					uo.Amount = out.Amount.String()
+				}
+				uo.PublicHash = out.PublicHash.String()
+			}
+			if ur, err := e.utxoRecordOf(txn, height, index, uint16(n)); err == nil && ur != nil && ur.Spent {
+				uo.Spent = true
+				uo.SpentBy = ur.SpentBy.String()
+			}
+			d.Outputs = append(d.Outputs, uo)
+		}
+		return nil
+	})
+	return d
+}
@@ -100,10 +100,30 @@ func (web *WebServer) UpdateRender() error {
 	templateMap[""] = tds
 
 	web.updateRender("", "/pages", templateMap)
+	web.loadErrorPages()
 
 	return nil
 }
 
+// loadErrorPages loads the error pages which are the whole documents without the layout
+func (web *WebServer) loadErrorPages() {
+	d, err := web.assets.Open("/errors")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	fi, err := d.Readdir(1)
+	for err == nil {
+		if !fi[0].IsDir() {
+			data := web.assetToData("/errors/" + fi[0].Name())
+			t := template.New(fi[0].Name())
+			template.Must(t.Parse(string(data)))
+			web.templates["errors/"+fi[0].Name()] = t
+		}
+		fi, err = d.Readdir(1)
+	}
+}
+
 func (web *WebServer) loadTemplates(prefix string, layout http.File, templateMap map[string][][]byte) [][]byte {
 	layoutData := web.assetToData("/layout/" + prefix + "layout.html")
 	baseData := web.assetToData("/layout/" + prefix + "base.html")
@@ -174,5 +194,8 @@ func (web *WebServer) Render(w io.Writer, name string, data interface{}, c echo.
 		err := errors.New("Template not found -> " + name)
 		return err
 	}
+	if tmpl.Lookup("base.html") == nil {
+		return tmpl.Execute(w, data)
+	}
 	return tmpl.ExecuteTemplate(w, "base.html", data)
 }
@@ -1,29 +1,41 @@
 var ChainInfoTableAjax={
+    chainTemplate: `
+<tr class="{oddeven}">
+    <td>{Chain}</td>
+    <td><a href="/blockDetail?height={Height}" target="_BLANK">{Height}</a></td>
+    <td>{Blocks}</td>
+    <td>{Txs}</td>
+    <td>{LastBlockTime}</td>
+    <td>{Formulators}</td>
+</tr>
+    `,
+    Chain:function(data, i){
+        var t = ChainInfoTableAjax.chainTemplate+""
+        t = t.replace(/{oddeven}/g, (i%2==0)?"odd":"even")
+        for (var k in data) {
+            if (data.hasOwnProperty(k)) {
+                var v = data[k]
+                t = t.replace(new RegExp("{"+k+"}", 'g'), v)
+            }
+        }
+        return t
+    },
     reload:function(){
-        ChainInfoTableAjax.obj.ajax.reload()
+        $.ajax({
+            url : "/data/chainInfoTable.data",
+            dataType : 'json',
+            success : function (d) {
+                var data = d.aaData;
+                var tbody = $("#fleta_chain_info tbody");
+                tbody.empty();
+                for (var i = 0 ; i < data.length ; i++) {
+                    tbody.append(ChainInfoTableAjax.Chain(data[i], i))
+                }
+            }
+        })
     },
-    obj : null,
     init:function(recursive){
-        ChainInfoTableAjax.obj = $("#fleta_chain_info").DataTable({
-            responsive:!0,
-            searchDelay:500,
-            processing:!0,
-            serverSide:!0,
-            paging: false,
-            searching: false,
-            info: false,
-            ordering: false,
-            ajax:"/data/chainInfoTable.data",
-            columns:[{data:"구분"},{data:"블록 크기"},{data:"블록 전송 시간"},{data:"블록 연결 시간"}],
-            columnDefs:[
-                {
-                    targets:0,
-                    render:function(a,e,t,n){
-                        return a
-                    }
-                },
-            ]
-        })
+        ChainInfoTableAjax.reload()
 
         if (recursive !== false) {
             setInterval( function () {
@@ -32,6 +44,3 @@ var ChainInfoTableAjax={
         }
     }
 };
-
-
-
@@ -256,6 +256,11 @@ function formatDate(date, format, utc) {
     return format;
 };
 
+// escapeHtml escapes the text to be put into the html templates
+function escapeHtml(text) {
+    return String(text).replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;").replace(/"/g, "&quot;").replace(/'/g, "&#39;");
+}
+
 function sendNewAccountTx () {
     $.ajax({
         url : "/tx/CreateAccount.tx",
@@ -287,4 +292,60 @@ function sendWithdrawtx () {
             // alert("send")
         }
     })
-}
\ No newline at end of file
+}
+// LiveFeed subscribes the topics of the /ws live feed, a topic can have many listeners
+// the callback is called with the data of every message of the topic,
+// the fallback is called once when the websocket is not available or the connection is lost
+var LiveFeed = {
+    socket: null,
+    topics: {},
+    on: function (topic, callback, fallback) {
+        var subscribed = LiveFeed.topics.hasOwnProperty(topic)
+        if (!subscribed) {
+            LiveFeed.topics[topic] = []
+        }
+        LiveFeed.topics[topic].push({callback: callback, fallback: fallback, failed: false})
+        if (LiveFeed.socket == null) {
+            LiveFeed.connect()
+        } else if (LiveFeed.socket.readyState == 1 && !subscribed) {
+            LiveFeed.socket.send(JSON.stringify({action: "subscribe", topics: [topic]}))
+        }
+    },
+    connect: function () {
+        if (!window.WebSocket) {
+            LiveFeed.fail()
+            return
+        }
+        var scheme = (location.protocol == "https:") ? "wss://" : "ws://"
+        var socket = new WebSocket(scheme + location.host + "/ws")
+        socket.onopen = function () {
+            socket.send(JSON.stringify({action: "subscribe", topics: Object.keys(LiveFeed.topics)}))
+        }
+        socket.onmessage = function (ev) {
+            var m = JSON.parse(ev.data)
+            var listeners = LiveFeed.topics[m.topic]
+            if (void 0 === listeners) {
+                return
+            }
+            for (var i = 0 ; i < listeners.length ; i++) {
+                listeners[i].callback(m.data)
+            }
+        }
+        socket.onclose = function () {
+            LiveFeed.socket = null
+            LiveFeed.fail()
+        }
+        LiveFeed.socket = socket
+    },
+    fail: function () {
+        for (var k in LiveFeed.topics) {
+            var listeners = LiveFeed.topics[k]
+            for (var i = 0 ; i < listeners.length ; i++) {
+                if (!listeners[i].failed) {
+                    listeners[i].failed = true
+                    listeners[i].fallback()
+                }
+            }
+        }
+    }
+};
@@ -4,18 +4,50 @@ var CurrentChainInfoAjax={
             url : "/data/currentChainInfo.data",
             dataType : 'json',
             success : function (data) {
-                $("#total_formulators").html(numberWithCommas(data.foumulators))
-                $("#total_blocks").html(numberWithCommas(data.blocks))
-                $("#total_transactions").html(numberWithCommas(data.transactions))
+                CurrentChainInfoAjax.showCounts(data.foumulators, data.blocks, data.transactions, data.tps, data.peakTps)
             }
         })
+        $.ajax({
+            url : "/data/status.data",
+            dataType : 'json',
+            success : function (data) {
+                CurrentChainInfoAjax.showProgress(data.indexedHeight, data.chainHeight, data.progress)
+            }
+        })
+    },
+    // push shows the chain info pushed by the live feed
+    push : function(info) {
+        CurrentChainInfoAjax.showCounts(info.formulators, info.height, info.transactions, info.tps, info.peakTps)
+        CurrentChainInfoAjax.showProgress(info.indexedHeight, info.height, (info.height > 0) ? info.indexedHeight * 100 / info.height : 100)
+    },
+    showCounts : function(formulators, blocks, transactions, tps, peakTps) {
+        $("#total_formulators").html(numberWithCommas(formulators))
+        $("#total_blocks").html(numberWithCommas(blocks))
+        $("#total_transactions").html(numberWithCommas(transactions))
+        if (tps) {
+            $("#currentTps").html(tps["1m"].toFixed(2))
+        }
+        if (peakTps) {
+            var peak = peakTps["1m"]
+            $("#peakTps").html(peak.tps.toFixed(2)).attr("title", "blocks " + peak.fromHeight + " - " + peak.toHeight)
+        }
+    },
+    showProgress : function(indexedHeight, chainHeight, progress) {
+        if (indexedHeight < chainHeight) {
+            $("#indexing_progress").html("indexing " + Math.floor(progress) + "%").show()
+        } else {
+            $("#indexing_progress").hide()
+        }
     },
     init:function(recursive){
         CurrentChainInfoAjax.reload()
         if (recursive !== false) {
-            setInterval( function () {
-                CurrentChainInfoAjax.reload();
-            }, 3000 );
+            // the live feed pushes the chain info, the polling is the fallback
+            LiveFeed.on("chainInfo", CurrentChainInfoAjax.push, function () {
+                setInterval( function () {
+                    CurrentChainInfoAjax.reload();
+                }, 3000 );
+            });
         }
     }
 };
@@ -11,7 +11,7 @@ var LastestBlocksAjax={
     observersTemplate: `
 <tr class="{oddeven}">
     <td><a href="/blockDetail?height={Block Height}" title="{Block Hash}" target="_BLANK">{Block Height}</a></td>
-    <td>{Formulator}</td>
+    <td><a href="/account?addr={Formulator}" title="{Formulator}">{Formulator}</a> <span class="accountName">{FormulatorName}</span></td>
     <td>{OB1}</td>
     <td>{OB2}</td>
     <td>{OB3}</td>
@@ -22,7 +22,7 @@ var LastestBlocksAjax={
     formulratorTemplate: `
 <tr class="{oddeven}">
     <td><a href="/blockDetail?height={Block Height}" title="{Block Hash}" target="_BLANK">{Block Height}</a></td>
-    <td>{Formulator}</td>
+    <td><a href="/account?addr={Formulator}" title="{Formulator}">{Formulator}</a> <span class="accountName">{FormulatorName}</span></td>
     <td>{BlockCount}</td>
 </tr>
     `,
@@ -56,7 +56,7 @@ var LastestBlocksAjax={
         for (var k in data) {
             if (data.hasOwnProperty(k)) {
                 var v = data[k]
-                t = t.replace(new RegExp("{"+k+"}", 'g'), v)
+                t = t.replace(new RegExp("{"+k+"}", 'g'), escapeHtml(v))
             }
         }
 
@@ -79,7 +79,7 @@ var LastestBlocksAjax={
         for (var k in data) {
             if (data.hasOwnProperty(k)) {
                 var v = data[k]
-                t = t.replace(new RegExp("{"+k+"}", 'g'), v)
+                t = t.replace(new RegExp("{"+k+"}", 'g'), escapeHtml(v))
             }
         }
         return t
@@ -90,51 +90,70 @@ var LastestBlocksAjax={
         for (var k in data) {
             if (data.hasOwnProperty(k)) {
                 var v = data[k]
-                t = t.replace(new RegExp("{"+k+"}", 'g'), v)
+                t = t.replace(new RegExp("{"+k+"}", 'g'), escapeHtml(v))
             }
         }
         return t;
         
     },
+    rows: [],
     reload:function(){
         $.ajax({
             url : "/data/lastestBlocks.data",
             dataType : 'json',
             success : function (d) {
-                var data = d.aaData;
-                var tbody = $("#fleta_blocks tbody");
-                var ths = $("#fleta_blocks thead th");
-                tbody.empty();
-                
-                ths.removeAttr("style")
-                if (tbody.width() <= 710) {
-                    ths.eq(3).hide()
-                }
-                if (tbody.width() <= 400) {
-                    ths.eq(2).hide()
-                    ths.eq(4).hide()
-                }
+                LastestBlocksAjax.show(d.aaData)
+            }
+        })
+    },
+    // push prepends the pushed block which has the same fields as the rows of lastestBlocks.data
+    push:function(block){
+        var rows = LastestBlocksAjax.rows
+        for (var i = 0 ; i < rows.length ; i++) {
+            if (rows[i]["Block Height"] == block["Block Height"]) {
+                return
+            }
+        }
+        LastestBlocksAjax.show([block].concat(rows).slice(0, 8))
+    },
+    show:function(data){
+        // the rows are kept as they are given because the templates change the fields
+        LastestBlocksAjax.rows = data
+        var tbody = $("#fleta_blocks tbody");
+        var ths = $("#fleta_blocks thead th");
+        tbody.empty();
 
-                var otbody = $("#fletaObservers tbody");
-                otbody.empty();
+        ths.removeAttr("style")
+        if (tbody.width() <= 710) {
+            ths.eq(3).hide()
+        }
+        if (tbody.width() <= 400) {
+            ths.eq(2).hide()
+            ths.eq(4).hide()
+        }
 
-                var ftbody = $("#fletaFormulrator tbody");
-                ftbody.empty();
+        var otbody = $("#fletaObservers tbody");
+        otbody.empty();
 
-                for (var i = 0 ; i < data.length ; i++) {
-                    tbody.append(LastestBlocksAjax.lastestBlocks(data[i], tbody.width(), i))
-                    otbody.append(LastestBlocksAjax.Observers(data[i], i))
-                    ftbody.append(LastestBlocksAjax.Formulrator(data[i], i))
-                }
-            }
-        })
+        var ftbody = $("#fletaFormulrator tbody");
+        ftbody.empty();
+
+        for (var i = 0 ; i < data.length ; i++) {
+            var r = $.extend({}, data[i])
+            tbody.append(LastestBlocksAjax.lastestBlocks(r, tbody.width(), i))
+            otbody.append(LastestBlocksAjax.Observers(r, i))
+            ftbody.append(LastestBlocksAjax.Formulrator(r, i))
+        }
     },
     init:function(recursive){
         LastestBlocksAjax.reload()
         if (recursive !== false) {
-            setInterval( function () {
-                LastestBlocksAjax.reload();
-            }, 3000 );
+            // the live feed pushes the blocks, the polling is the fallback
+            LiveFeed.on("blocks", LastestBlocksAjax.push, function () {
+                setInterval( function () {
+                    LastestBlocksAjax.reload();
+                }, 3000 );
+            });
         }
     }
 };
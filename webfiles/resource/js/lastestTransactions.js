@@ -1,22 +1,40 @@
 var LastestTransactionsAjax={
+    rows: [],
     reload : function() {
         $.ajax({
             url : "/data/lastestTransactions.data",
             dataType : 'json',
             success : function (data) {
-                var $txs = $("#fleta-lastest-transactions");
-
-                $txs.html("")
-
-                for (var i = 0 ; i < data.length ; i++) {
-                    var r = data[i]
-                    var $e = LastestTransactionsAjax.render(r.Time,r.TxHash,r.TxType)
-                    $txs.append($e)
-                }
+                LastestTransactionsAjax.show(data)
             }
         })
 
     },
+    // push prepends the recent transactions of the pushed block
+    push : function(block) {
+        if (block.Transactions.length == 0) {
+            return
+        }
+        var rows = LastestTransactionsAjax.rows
+        for (var i = 0 ; i < rows.length ; i++) {
+            if (rows[i].TxHash == block.Transactions[0].TxHash) {
+                return
+            }
+        }
+        LastestTransactionsAjax.show(block.Transactions.concat(LastestTransactionsAjax.rows).slice(0, 8))
+    },
+    show : function(data) {
+        LastestTransactionsAjax.rows = data
+        var $txs = $("#fleta-lastest-transactions");
+
+        $txs.html("")
+
+        for (var i = 0 ; i < data.length ; i++) {
+            var r = data[i]
+            var $e = LastestTransactionsAjax.render(r.Time,r.TxHash,r.TxType)
+            $txs.append($e)
+        }
+    },
     render: function (time, hash, type) {
         var $template = $("#transactions-item-template").clone()
         $template.find(".timeline-3_item").addClass(type.replace(/\./gi, ""))
@@ -32,9 +50,12 @@ var LastestTransactionsAjax={
         LastestTransactionsAjax.reload();
 
         if (recursive !== false) {
-            setInterval( function () {
-                LastestTransactionsAjax.reload();
-            }, 3000 );
+            // the transactions come with the pushed blocks, the polling is the fallback
+            LiveFeed.on("blocks", LastestTransactionsAjax.push, function () {
+                setInterval( function () {
+                    LastestTransactionsAjax.reload();
+                }, 3000 );
+            });
         }
     }
 };